@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/tobiaspartzsch/pokedex/internal/pokecache"
+)
+
+// cachePreviewBytes is how much of a cached body `cache get` prints.
+const cachePreviewBytes = 500
+
+func commandCache(ctx context.Context, cfg *Config, args []string) error {
+	if len(args) == 0 {
+		return cacheConfig(cfg)
+	}
+	rest := args[1:]
+	switch args[0] {
+	case "stats":
+		return cacheStats(cfg)
+	case "list":
+		return cacheList(cfg, firstOr(rest, ""))
+	case "get":
+		if len(rest) == 0 {
+			return fmt.Errorf("cache get requires a url")
+		}
+		return cacheGet(cfg, rest[0])
+	case "clear":
+		return cacheClear(cfg, firstOr(rest, ""))
+	case "prune":
+		return cachePrune(cfg)
+	}
+	return fmt.Errorf("unknown cache subcommand %q, expected stats, list, get, clear or prune", args[0])
+}
+
+func cacheConfig(cfg *Config) error {
+	stats := cfg.PokeCache.Stats()
+	fmt.Printf("TTL: %v (then served stale for up to %v while refreshing)\n", cfg.Settings.CacheTTL, cfg.Settings.CacheStaleTTL)
+	fmt.Printf("Reap interval: %v\n", cfg.Settings.CacheReap)
+	fmt.Printf("Entries: %d of at most %s\n", stats.Entries, limitString(int64(cfg.Settings.CacheMaxEntries)))
+	fmt.Printf("Bytes: %d of at most %s\n", stats.Bytes, limitString(cfg.Settings.CacheMaxBytes))
+	if disk := diskStore(cfg.PokeCache); disk != nil {
+		fmt.Printf("Persisted in: %s\n", disk.Dir())
+	} else {
+		fmt.Println("Persisted in: (memory only)")
+	}
+	return nil
+}
+
+func cacheStats(cfg *Config) error {
+	for _, tier := range cacheTiers(cfg.PokeCache) {
+		stats := tier.Stats()
+		lookups := stats.Hits + stats.Misses
+		hitRate := 0.0
+		if lookups > 0 {
+			hitRate = 100 * float64(stats.Hits) / float64(lookups)
+		}
+		fmt.Printf("%s:\n", tierName(tier))
+		fmt.Printf("  entries:     %d (%d bytes)\n", stats.Entries, stats.Bytes)
+		fmt.Printf("  hits:        %d (%.1f%%)\n", stats.Hits, hitRate)
+		fmt.Printf("  misses:      %d\n", stats.Misses)
+		fmt.Printf("  evictions:   %d\n", stats.Evictions)
+		fmt.Printf("  expirations: %d\n", stats.Expirations)
+	}
+	return nil
+}
+
+func cacheList(cfg *Config, prefix string) error {
+	infos := cfg.PokeCache.List(prefix)
+	if len(infos) == 0 {
+		fmt.Println("No cached responses")
+		return nil
+	}
+	now := time.Now()
+	for _, info := range infos {
+		state := ""
+		if info.Stale {
+			state = ", stale"
+		}
+		fmt.Printf(" - %s (%d bytes, %v old%s)\n", info.Key, info.Size, now.Sub(info.CreatedAt).Round(time.Second), state)
+	}
+	return nil
+}
+
+func cacheGet(cfg *Config, url string) error {
+	// Looking must not change the stats or eviction order being inspected
+	entry, ok := cfg.PokeCache.Peek(url)
+	if !ok {
+		fmt.Printf("%s is not cached\n", url)
+		return nil
+	}
+	fmt.Printf("Cached: %v ago\n", time.Since(entry.CreatedAt).Round(time.Second))
+	fmt.Printf("Stale: %v\n", entry.Stale)
+	if entry.ETag != "" {
+		fmt.Printf("ETag: %s\n", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		fmt.Printf("Last-Modified: %s\n", entry.LastModified)
+	}
+	fmt.Printf("Size: %d bytes\n", len(entry.Val))
+	preview := entry.Val
+	if len(preview) > cachePreviewBytes {
+		preview = preview[:cachePreviewBytes]
+	}
+	fmt.Printf("%s", preview)
+	if len(preview) < len(entry.Val) {
+		fmt.Print("...")
+	}
+	fmt.Println()
+	return nil
+}
+
+func cacheClear(cfg *Config, prefix string) error {
+	infos := cfg.PokeCache.List(prefix)
+	for _, info := range infos {
+		cfg.PokeCache.Delete(info.Key)
+	}
+	fmt.Printf("Removed %d cached responses\n", len(infos))
+	return nil
+}
+
+func cachePrune(cfg *Config) error {
+	disk := diskStore(cfg.PokeCache)
+	if disk == nil {
+		fmt.Println("The cache is not persisted, nothing to prune")
+		return nil
+	}
+	removed, err := disk.Prune()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d files from %s\n", removed, disk.Dir())
+	return nil
+}
+
+func limitString(limit int64) string {
+	if limit <= 0 {
+		return "unbounded"
+	}
+	return fmt.Sprint(limit)
+}
+
+func firstOr(args []string, fallback string) string {
+	if len(args) == 0 {
+		return fallback
+	}
+	return args[0]
+}
+
+// newCache builds the response cache from the settings: memory in front
+// of disk, or memory only if the cache directory can't be used.
+func newCache(s settings) pokecache.Store {
+	memory := pokecache.New(pokecache.Config{
+		TTL:          s.CacheTTL,
+		StaleTTL:     s.CacheStaleTTL,
+		ReapInterval: s.CacheReap,
+		MaxEntries:   s.CacheMaxEntries,
+		MaxBytes:     s.CacheMaxBytes,
+	})
+	// A recording has to see every request and a replay must not depend
+	// on what earlier sessions left on disk.
+	if s.CacheDir == "" || s.Record != "" || s.Replay != "" {
+		return memory
+	}
+	disk, err := pokecache.NewDiskStore(s.CacheDir, s.CacheTTL, s.CacheStaleTTL)
+	if err != nil {
+		log.Printf("not persisting the cache: %v", err)
+		return memory
+	}
+	if s.Offline {
+		// Old responses are all there is while offline, keep them.
+		disk.KeepExpired = true
+	} else {
+		go disk.Prune()
+	}
+	return pokecache.NewLayered(memory, disk)
+}
+
+// cacheTiers flattens layered stores into their tiers, fastest first.
+func cacheTiers(store pokecache.Store) []pokecache.Store {
+	layered, ok := store.(*pokecache.Layered)
+	if !ok {
+		return []pokecache.Store{store}
+	}
+	var tiers []pokecache.Store
+	for _, tier := range layered.Tiers() {
+		tiers = append(tiers, cacheTiers(tier)...)
+	}
+	return tiers
+}
+
+func tierName(store pokecache.Store) string {
+	switch s := store.(type) {
+	case *pokecache.Cache:
+		return "memory"
+	case *pokecache.DiskStore:
+		return "disk (" + s.Dir() + ")"
+	case pokecache.Noop:
+		return "disabled"
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", store), "*")
+}
+
+// diskStore finds the persistent tier of store, if it has one.
+func diskStore(store pokecache.Store) *pokecache.DiskStore {
+	for _, tier := range cacheTiers(store) {
+		if disk, ok := tier.(*pokecache.DiskStore); ok {
+			return disk
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tobiaspartzsch/pokedex/internal/pokeapi"
+	"github.com/tobiaspartzsch/pokedex/internal/pokecache"
+)
+
+func TestCacheClearPrefix(t *testing.T) {
+	memory := pokecache.New(pokecache.Config{TTL: time.Hour})
+	defer memory.Close()
+	memory.Add("https://pokeapi.co/api/v2/pokemon/pikachu", []byte("{}"))
+	memory.Add("https://pokeapi.co/api/v2/pokemon/raichu", []byte("{}"))
+	memory.Add("https://pokeapi.co/api/v2/location-area/canalave-city-area", []byte("{}"))
+	cfg := &Config{PokeCache: memory}
+
+	err := commandCache(context.Background(), cfg, []string{"clear", "https://pokeapi.co/api/v2/pokemon/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	infos := memory.List("")
+	if len(infos) != 1 || infos[0].Key != "https://pokeapi.co/api/v2/location-area/canalave-city-area" {
+		t.Errorf("expected only the location area to be left, got %+v", infos)
+	}
+}
+
+func TestCacheGetIsNotAUse(t *testing.T) {
+	memory := pokecache.New(pokecache.Config{TTL: time.Hour})
+	defer memory.Close()
+	disk, err := pokecache.NewDiskStore(t.TempDir(), time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "https://pokeapi.co/api/v2/pokemon/pikachu"
+	disk.Add(url, []byte(`{"name": "pikachu"}`))
+	cfg := &Config{PokeCache: pokecache.NewLayered(memory, disk)}
+
+	output := captureOutput(t, func() {
+		if err := commandCache(context.Background(), cfg, []string{"get", url}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(output, `{"name": "pikachu"}`) {
+		t.Errorf("expected the cached body, got %q", output)
+	}
+	if memory.Stats().Entries != 0 || disk.Stats().Hits != 0 {
+		t.Errorf("expected cache get to leave the cache as it was, got memory %+v, disk %+v", memory.Stats(), disk.Stats())
+	}
+}
+
+func TestCacheTiers(t *testing.T) {
+	memory := pokecache.New(pokecache.Config{TTL: time.Hour})
+	defer memory.Close()
+	disk, err := pokecache.NewDiskStore(t.TempDir(), time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := pokecache.NewLayered(memory, disk)
+
+	tiers := cacheTiers(store)
+	if len(tiers) != 2 || tierName(tiers[0]) != "memory" {
+		t.Errorf("unexpected tiers %v", tiers)
+	}
+	if diskStore(store) != disk {
+		t.Errorf("expected to find the disk tier")
+	}
+	if diskStore(memory) != nil {
+		t.Errorf("expected a memory only cache to have no disk tier")
+	}
+}
+
+func TestGoOffline(t *testing.T) {
+	client := pokeapi.NewClient("", time.Second)
+	if err := goOffline(client, ""); err != nil {
+		t.Fatal(err)
+	}
+	if client.Revalidate || client.Limiter != nil {
+		t.Errorf("expected an offline client to neither revalidate nor throttle, got %+v", client)
+	}
+	_, err := client.GetPokemon(context.Background(), client.URL("pokemon", "pikachu"))
+	if !errors.Is(err, pokeapi.ErrOffline) {
+		t.Errorf("expected ErrOffline, got %v", err)
+	}
+}
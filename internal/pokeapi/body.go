@@ -0,0 +1,101 @@
+package pokeapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+)
+
+// DefaultMaxResponseBytes caps response bodies. The largest PokeAPI
+// resources, Pokemon with every move and sprite, are a few hundred KB.
+const DefaultMaxResponseBytes = 8 << 20
+
+// snippetSize is how much of a body error messages quote.
+const snippetSize = 512
+
+var (
+	ErrResponseTooLarge      = errors.New("response body too large")
+	ErrUnexpectedContentType = errors.New("unexpected Content-Type")
+)
+
+// bodyReader reads a response body, failing with ErrResponseTooLarge once
+// it is longer than max bytes. It keeps the start of the body for error
+// messages and remembers read errors, to tell a broken connection from
+// malformed JSON.
+type bodyReader struct {
+	r    io.Reader
+	max  int64 // 0 or less means no limit
+	read int64
+	head []byte
+	err  error
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	if b.max > 0 {
+		if b.read >= b.max {
+			// Anything beyond the limit, even a single byte, is too much
+			var probe [1]byte
+			n, err := b.r.Read(probe[:])
+			if n > 0 {
+				return 0, ErrResponseTooLarge
+			}
+			return 0, b.note(err)
+		}
+		if remaining := b.max - b.read; int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+	n, err := b.r.Read(p)
+	b.read += int64(n)
+	if keep := min(n, snippetSize-len(b.head)); keep > 0 {
+		b.head = append(b.head, p[:keep]...)
+	}
+	return n, b.note(err)
+}
+
+func (b *bodyReader) note(err error) error {
+	if err != nil && err != io.EOF {
+		b.err = err
+	}
+	return err
+}
+
+// snippet reads up to snippetSize bytes of the body, if they were not
+// read yet, and returns them.
+func (b *bodyReader) snippet() []byte {
+	if missing := snippetSize - len(b.head); missing > 0 && b.err == nil {
+		io.Copy(io.Discard, io.LimitReader(b, int64(missing)))
+	}
+	return b.head
+}
+
+// wrap turns an error from reading or decoding the body of url into a
+// *TransportError, a *DecodeError or ErrResponseTooLarge.
+func (b *bodyReader) wrap(url string, err error) error {
+	switch {
+	case errors.Is(err, ErrResponseTooLarge):
+		return fmt.Errorf("%s: %w (limit %d bytes)", url, ErrResponseTooLarge, b.max)
+	case b.err != nil:
+		return &TransportError{URL: url, Err: fmt.Errorf("reading response body: %w", b.err)}
+	}
+	return &DecodeError{URL: url, Err: err, Snippet: b.head}
+}
+
+// jsonContentType reports whether a response with Content-Type ct may be
+// decoded as JSON. Besides the JSON types, text/plain is accepted, as
+// static mirrors of the API serve their files with it, and so is a
+// missing Content-Type.
+func jsonContentType(ct string) bool {
+	if ct == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" ||
+		strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "text/plain"
+}
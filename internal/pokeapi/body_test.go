@@ -0,0 +1,119 @@
+package pokeapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tobiaspartzsch/pokedex/internal/pokecache"
+)
+
+func TestMaxResponseBytes(t *testing.T) {
+	body := `{"name": "pikachu", "padding": "` + strings.Repeat("x", 1000) + `"}`
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	for _, cached := range []bool{false, true} {
+		c := newTestClient(server.URL)
+		if cached {
+			memory := pokecache.New(pokecache.Config{TTL: time.Hour})
+			defer memory.Close()
+			c.Cache = memory
+		}
+
+		c.MaxResponseBytes = int64(len(body))
+		if p, err := c.GetPokemon(context.Background(), c.URL("pokemon", "pikachu")); err != nil || p.Name != "pikachu" {
+			t.Fatalf("cached=%v: a body of exactly the limit should decode, got %+v, %v", cached, p, err)
+		}
+
+		c.MaxResponseBytes = int64(len(body)) - 1
+		requests = 0
+		_, err := c.GetPokemon(context.Background(), c.URL("pokemon", "raichu"))
+		if !errors.Is(err, ErrResponseTooLarge) {
+			t.Errorf("cached=%v: expected ErrResponseTooLarge, got %v", cached, err)
+		}
+		if requests != 1 {
+			t.Errorf("cached=%v: a response that is too large should not be retried, got %d requests", cached, requests)
+		}
+	}
+}
+
+func TestUnexpectedContentType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pokemon/plain" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Write([]byte(`{"name": "plain"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html>Please log in to the hotel Wi-Fi</html>`))
+	}))
+	defer server.Close()
+	c := newTestClient(server.URL)
+
+	_, err := c.GetPokemon(context.Background(), c.URL("pokemon", "pikachu"))
+	var decodeErr *DecodeError
+	if !errors.Is(err, ErrUnexpectedContentType) || !errors.As(err, &decodeErr) {
+		t.Fatalf("expected a DecodeError for the HTML page, got %v", err)
+	}
+	if !strings.Contains(string(decodeErr.Snippet), "hotel Wi-Fi") {
+		t.Errorf("expected the start of the page in the error, got %q", decodeErr.Snippet)
+	}
+
+	if p, err := c.GetPokemon(context.Background(), c.URL("pokemon", "plain")); err != nil || p.Name != "plain" {
+		t.Errorf("expected text/plain JSON to be accepted, got %+v, %v", p, err)
+	}
+}
+
+func TestErrorBodiesAreTruncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pokemon/garbled" {
+			w.Write([]byte(`{"name": ` + strings.Repeat("?", 10*snippetSize)))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(bytes.Repeat([]byte("e"), 10*snippetSize))
+	}))
+	defer server.Close()
+	c := newTestClient(server.URL)
+	c.Retry.MaxAttempts = 1
+
+	_, err := c.GetPokemon(context.Background(), c.URL("pokemon", "pikachu"))
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || len(statusErr.Body) != snippetSize {
+		t.Errorf("expected a StatusError with a %d byte body, got %v", snippetSize, err)
+	}
+
+	_, err = c.GetPokemon(context.Background(), c.URL("pokemon", "garbled"))
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || len(decodeErr.Snippet) > snippetSize {
+		t.Errorf("expected a DecodeError with at most %d bytes of body, got %v", snippetSize, err)
+	}
+}
+
+func TestJSONContentType(t *testing.T) {
+	for ct, want := range map[string]bool{
+		"":                                true,
+		"application/json":                true,
+		"application/json; charset=utf-8": true,
+		"application/problem+json":        true,
+		"text/plain; charset=utf-8":       true,
+		"text/html; charset=utf-8":        false,
+		"application/octet-stream":        false,
+		"not a media type;;":              false,
+	} {
+		if got := jsonContentType(ct); got != want {
+			t.Errorf("jsonContentType(%q) = %v, expected %v", ct, got, want)
+		}
+	}
+}
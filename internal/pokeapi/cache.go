@@ -0,0 +1,105 @@
+package pokeapi
+
+import (
+	"context"
+
+	"github.com/tobiaspartzsch/pokedex/internal/pokecache"
+)
+
+// Link is anything that points to another resource, such as a
+// NamedAPIResource or an APIResource.
+type Link interface {
+	ResourceURL() string
+}
+
+func (r NamedAPIResource) ResourceURL() string { return r.URL }
+func (r APIResource) ResourceURL() string      { return r.URL }
+
+// Resolve fetches the resource link points to and decodes it into target,
+// which must be a pointer, e.g.
+//
+//	var species PokemonSpecies
+//	err := c.Resolve(ctx, pokemon.Species, &species)
+func (c *Client) Resolve(ctx context.Context, link Link, target any) error {
+	return c.get(ctx, link.ResourceURL(), target)
+}
+
+// Resolve is the typed form of Client.Resolve, e.g.
+//
+//	species, err := Resolve[PokemonSpecies](ctx, c, pokemon.Species)
+func Resolve[T any](ctx context.Context, c *Client, link Link) (T, error) {
+	var v T
+	err := c.Resolve(ctx, link, &v)
+	return v, err
+}
+
+// decode decodes a body that was fetched from url, dropping it from the
+// cache if it can't be decoded so it is not served again.
+func (c *Client) decode(url string, body []byte, target any) error {
+	err := decodeInto(url, body, target)
+	if err != nil && c.Cache != nil {
+		c.Cache.Delete(url)
+	}
+	return err
+}
+
+// fetchCached returns the body of url, from c.Cache if possible. Stale
+// entries are served right away and, if c.Revalidate is set, refreshed in
+// the background, and concurrent misses for the same url share a single
+// fetch. c.Cache must not be nil.
+func (c *Client) fetchCached(ctx context.Context, url string) ([]byte, error) {
+	if entry, exists := c.Cache.Lookup(url); exists {
+		if entry.Stale && c.Revalidate {
+			go c.revalidate(url, entry)
+		}
+		return entry.Val, nil
+	}
+
+	return c.inflight.Do(ctx, url, func(ctx context.Context) ([]byte, error) {
+		// Someone else may have just finished fetching it. Peek, as the
+		// miss was counted by the Lookup above
+		if entry, exists := c.Cache.Peek(url); exists && !entry.Stale {
+			return entry.Val, nil
+		}
+
+		res, err := c.fetch(ctx, url, Validators{}, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// An entry too large to be cached is still usable
+		c.Cache.Set(url, pokecache.Entry{
+			Val:          res.Body,
+			ETag:         res.Validators.ETag,
+			LastModified: res.Validators.LastModified,
+		})
+		return res.Body, nil
+	})
+}
+
+// revalidate refreshes a stale cache entry, conditionally if the entry
+// has validators. An unchanged resource only extends the entry's TTL.
+// Failures are ignored, the stale entry stays until it expires.
+func (c *Client) revalidate(url string, entry pokecache.Entry) {
+	c.inflight.Do(context.Background(), "revalidate "+url, func(ctx context.Context) ([]byte, error) {
+		validators := Validators{ETag: entry.ETag, LastModified: entry.LastModified}
+		res, err := c.fetch(ctx, url, validators, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		fresh := pokecache.Entry{
+			Val:          res.Body,
+			ETag:         res.Validators.ETag,
+			LastModified: res.Validators.LastModified,
+		}
+		if res.NotModified {
+			fresh.Val = entry.Val
+			if fresh.ETag == "" && fresh.LastModified == "" {
+				fresh.ETag, fresh.LastModified = entry.ETag, entry.LastModified
+			}
+		}
+		c.Cache.Set(url, fresh)
+		return fresh.Val, nil
+	})
+}
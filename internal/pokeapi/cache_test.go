@@ -0,0 +1,207 @@
+package pokeapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tobiaspartzsch/pokedex/internal/pokecache"
+)
+
+func newCachedTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *pokecache.Cache) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	cache := pokecache.New(pokecache.Config{TTL: time.Hour})
+	t.Cleanup(func() { cache.Close() })
+	c := newTestClient(server.URL)
+	c.Cache = cache
+	return c, cache
+}
+
+func TestGetCachesRawBody(t *testing.T) {
+	const body = `{"id": 25, "name": "pikachu", "base_experience": 112, "unknown_field": true}`
+	var requests atomic.Int32
+	c, cache := newCachedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		if r.URL.Path == "/pokemon/broken" {
+			w.Write([]byte("not json"))
+			return
+		}
+		w.Write([]byte(body))
+	})
+
+	url := c.URL("pokemon", "pikachu")
+	for range 2 {
+		pokemon, err := c.GetPokemon(context.Background(), url)
+		if err != nil || pokemon.BaseExperience != 112 {
+			t.Fatalf("unexpected result %+v, %v", pokemon, err)
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected the second Get to be served from the cache, got %d requests", requests.Load())
+	}
+	entry, ok := cache.Lookup(url)
+	if !ok || string(entry.Val) != body || entry.ETag != `"v1"` {
+		t.Errorf("expected the response to be cached as is with its ETag, got %q, %q", entry.Val, entry.ETag)
+	}
+
+	broken := c.URL("pokemon", "broken")
+	if _, err := c.GetPokemon(context.Background(), broken); err == nil {
+		t.Fatalf("expected a decode error")
+	}
+	if _, ok := cache.Get(broken); ok {
+		t.Errorf("expected a body that can't be decoded not to stay cached")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	c, _ := newCachedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pokemon-species/25/":
+			w.Write([]byte(`{"id": 25, "name": "pikachu", "capture_rate": 190, "evolution_chain": {"url": "http://` + r.Host + `/evolution-chain/10/"}}`))
+		case "/evolution-chain/10/":
+			w.Write([]byte(`{"id": 10, "chain": {"species": {"name": "pichu"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	link := NamedAPIResource{Name: "pikachu", URL: c.URL("pokemon-species", "25") + "/"}
+	species, err := Resolve[PokemonSpecies](context.Background(), c, link)
+	if err != nil || species.CaptureRate != 190 {
+		t.Fatalf("unexpected species %+v, %v", species, err)
+	}
+
+	var chain EvolutionChain
+	if err := c.Resolve(context.Background(), species.EvolutionChain, &chain); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if chain.Chain.Species.Name != "pichu" {
+		t.Errorf("unexpected chain %+v", chain)
+	}
+}
+
+func TestGetRevalidatesStaleEntries(t *testing.T) {
+	var conditional atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	var now atomic.Int64
+	now.Store(time.Now().UnixNano())
+	cache := pokecache.New(pokecache.Config{
+		TTL:      time.Minute,
+		StaleTTL: time.Hour,
+		Now:      func() time.Time { return time.Unix(0, now.Load()) },
+	})
+	defer cache.Close()
+	c := newTestClient(server.URL)
+	c.Cache = cache
+
+	url := c.URL("pokemon", "pikachu")
+	if _, err := c.GetPokemon(context.Background(), url); err != nil {
+		t.Fatal(err)
+	}
+	now.Add(int64(2 * time.Minute))
+
+	pokemon, err := c.GetPokemon(context.Background(), url)
+	if err != nil || pokemon.Name != "pikachu" {
+		t.Fatalf("expected the stale entry to be served, got %+v, %v", pokemon, err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if entry, ok := cache.Lookup(url); ok && !entry.Stale {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the entry to be revalidated")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if conditional.Load() != 1 {
+		t.Errorf("expected one conditional request, got %d", conditional.Load())
+	}
+}
+
+func TestGetWithoutRevalidation(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	var now atomic.Int64
+	now.Store(time.Now().UnixNano())
+	cache := pokecache.New(pokecache.Config{
+		TTL:      time.Minute,
+		StaleTTL: time.Hour,
+		Now:      func() time.Time { return time.Unix(0, now.Load()) },
+	})
+	defer cache.Close()
+	c := newTestClient(server.URL)
+	c.Cache = cache
+	c.Revalidate = false
+
+	url := c.URL("pokemon", "pikachu")
+	if _, err := c.GetPokemon(context.Background(), url); err != nil {
+		t.Fatal(err)
+	}
+	now.Add(int64(2 * time.Minute))
+
+	for range 3 {
+		if pokemon, err := c.GetPokemon(context.Background(), url); err != nil || pokemon.Name != "pikachu" {
+			t.Fatalf("expected the stale entry to be served, got %+v, %v", pokemon, err)
+		}
+	}
+	// Give a wrongly started refresh time to show up
+	time.Sleep(20 * time.Millisecond)
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected stale entries not to be refreshed, got %d requests", got)
+	}
+	if entry, ok := cache.Lookup(url); !ok || !entry.Stale {
+		t.Errorf("expected the entry to stay stale, got %+v, %v", entry, ok)
+	}
+}
+
+func TestGetCountsOneMissPerColdFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	disk, err := pokecache.NewDiskStore(t.TempDir(), time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	memory := pokecache.New(pokecache.Config{TTL: time.Hour})
+	cache := pokecache.NewLayered(memory, disk)
+	defer cache.Close()
+	c := newTestClient(server.URL)
+	c.Cache = cache
+
+	url := c.URL("pokemon", "pikachu")
+	for range 2 {
+		if _, err := c.GetPokemon(context.Background(), url); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stats := memory.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("expected one miss and one hit in memory, got %+v", stats)
+	}
+	if stats := disk.Stats(); stats.Hits != 0 || stats.Misses != 1 {
+		t.Errorf("expected one miss on disk, got %+v", stats)
+	}
+}
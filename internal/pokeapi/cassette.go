@@ -0,0 +1,176 @@
+package pokeapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotRecorded is returned by a ReplayTransport for requests that are
+// not on its cassette.
+var ErrNotRecorded = errors.New("request not recorded on the cassette")
+
+// A cassette is a directory with one JSON file per recorded request.
+// Requests are told apart by method, path and query, the host is ignored
+// so a cassette can be replayed against any base URL.
+type recording struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// RecordTransport is an http.RoundTripper that passes requests on to the
+// next transport and saves the responses to a cassette directory.
+// Transient failures such as 429 and 503 are not recorded, so the retry
+// that succeeds is what ends up on the cassette, and neither are
+// conditional requests.
+type RecordTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+// NewRecordTransport records to dir, creating it if needed. A nil next
+// means http.DefaultTransport.
+func NewRecordTransport(dir string, next http.RoundTripper) (*RecordTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("couldn't create cassette directory: %w", err)
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RecordTransport{dir: dir, next: next}, nil
+}
+
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err != nil || retryableStatus(res.StatusCode) || !recordable(req, res) {
+		return res, err
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	rec := recording{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: res.StatusCode,
+		Header: recordedHeader(res.Header),
+		Body:   string(body),
+	}
+	if err := writeRecording(filepath.Join(t.dir, cassetteFile(req)), rec); err != nil {
+		return nil, fmt.Errorf("couldn't record %s: %w", req.URL, err)
+	}
+	return res, nil
+}
+
+// ReplayTransport is an http.RoundTripper that answers requests from a
+// cassette recorded by a RecordTransport and never goes to the network.
+// Anything that was not recorded fails with ErrNotRecorded.
+type ReplayTransport struct {
+	dir string
+}
+
+// NewReplayTransport replays the cassette in dir.
+func NewReplayTransport(dir string) (*ReplayTransport, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("couldn't open cassette: %w", err)
+	}
+	return &ReplayTransport{dir: dir}, nil
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	data, err := os.ReadFile(filepath.Join(t.dir, cassetteFile(req)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL, ErrNotRecorded)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("broken recording of %s: %w", req.URL, err)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rec.Header,
+		Body:          io.NopCloser(strings.NewReader(rec.Body)),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}, nil
+}
+
+// recordable reports whether res may go on the cassette. Conditional
+// requests, such as cache revalidations, and their 304s are left off, as
+// a replayed 304 would answer a plain GET of the same URL without a body.
+func recordable(req *http.Request, res *http.Response) bool {
+	conditional := req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != ""
+	return !conditional && res.StatusCode != http.StatusNotModified
+}
+
+// recordedHeader keeps the headers that matter to the client, so that
+// cassettes don't change with every server-side date or trace id.
+func recordedHeader(h http.Header) http.Header {
+	kept := http.Header{}
+	for _, name := range []string{"Content-Type", "ETag", "Last-Modified"} {
+		if v := h.Values(name); len(v) > 0 {
+			kept[http.CanonicalHeaderKey(name)] = v
+		}
+	}
+	return kept
+}
+
+// cassetteFile names the recording of req after its path, to keep
+// cassettes readable, and a hash of method, path and sorted query.
+func cassetteFile(req *http.Request) string {
+	key := req.Method + " " + req.URL.Path
+	if query := req.URL.Query(); len(query) > 0 {
+		key += "?" + query.Encode()
+	}
+	sum := sha256.Sum256([]byte(key))
+
+	name := strings.Trim(req.URL.Path, "/")
+	name = strings.NewReplacer("/", "_", ".", "_").Replace(name)
+	if len(name) > 80 {
+		name = name[len(name)-80:]
+	}
+	return name + "-" + hex.EncodeToString(sum[:6]) + ".json"
+}
+
+func writeRecording(path string, rec recording) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
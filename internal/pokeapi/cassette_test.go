@@ -0,0 +1,89 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch {
+		case calls.Load() == 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/pokemon/pikachu" && r.Header.Get("If-None-Match") == `"v1"`:
+			w.WriteHeader(http.StatusNotModified)
+		case r.URL.Path == "/pokemon/pikachu":
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Date", "Mon, 02 Jan 2006 15:04:05 GMT")
+			w.Write([]byte(`{"id": 25, "name": "pikachu"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	recorder, err := NewRecordTransport(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newTestClient(server.URL)
+	c.HTTPClient.Transport = recorder
+	if _, err := c.GetPokemon(context.Background(), c.URL("pokemon", "pikachu")); err != nil {
+		t.Fatalf("unexpected error while recording: %v", err)
+	}
+	if _, err := c.GetPokemon(context.Background(), c.URL("pokemon", "missingno")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound while recording, got %v", err)
+	}
+	// A revalidation must not replace the recorded 200 with its 304
+	res, err := c.FetchRaw(context.Background(), c.URL("pokemon", "pikachu"), Validators{ETag: `"v1"`})
+	if err != nil || !res.NotModified {
+		t.Fatalf("expected a 304 while recording, got %+v, %v", res, err)
+	}
+	server.Close()
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 2 {
+		t.Errorf("expected the 200 and the 404 to be recorded but not the 503, got %d files", len(files))
+	}
+
+	replayer, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Replaying works against any host
+	c = newTestClient("http://replay.invalid")
+	c.HTTPClient.Transport = replayer
+
+	res, err = c.FetchRaw(context.Background(), c.URL("pokemon", "pikachu"), Validators{})
+	if err != nil || string(res.Body) != `{"id": 25, "name": "pikachu"}` || res.Validators.ETag != `"v1"` {
+		t.Fatalf("unexpected replay %+v, %v", res, err)
+	}
+	if _, err := c.GetPokemon(context.Background(), c.URL("pokemon", "missingno")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected the recorded 404 to be replayed, got %v", err)
+	}
+
+	_, err = c.GetPokemon(context.Background(), c.URL("pokemon", "raichu"))
+	if !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("expected ErrNotRecorded, got %v", err)
+	}
+}
+
+func TestCassetteFileIgnoresHostAndQueryOrder(t *testing.T) {
+	a, _ := http.NewRequest(http.MethodGet, "https://pokeapi.co/api/v2/location-area?offset=20&limit=20", nil)
+	b, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:8080/api/v2/location-area?limit=20&offset=20", nil)
+	c, _ := http.NewRequest(http.MethodGet, "https://pokeapi.co/api/v2/location-area?offset=40&limit=20", nil)
+	if cassetteFile(a) != cassetteFile(b) {
+		t.Errorf("expected %s and %s to share a recording", a.URL, b.URL)
+	}
+	if cassetteFile(a) == cassetteFile(c) {
+		t.Errorf("expected different pages to have different recordings")
+	}
+}
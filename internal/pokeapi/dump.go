@@ -0,0 +1,194 @@
+package pokeapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrOffline is returned for requests that can't be answered without
+// going to the network.
+var ErrOffline = errors.New("not available offline")
+
+// OfflineTransport is an http.RoundTripper that refuses every request.
+type OfflineTransport struct{}
+
+func (OfflineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("%s: %w", req.URL, ErrOffline)
+}
+
+// DumpTransport is an http.RoundTripper that answers requests from a local
+// copy of the PokeAPI data in the layout of the api-data repository
+// (https://github.com/PokeAPI/api-data): dir/api/v2/<endpoint>/<id>/index.json.
+// The dump only knows resources by id, names are looked up in the
+// endpoint's index. Anything missing from the dump fails with ErrOffline.
+type DumpTransport struct {
+	dir     string
+	baseURL *url.URL
+
+	mu      sync.Mutex
+	indexes map[string]map[string]string // endpoint -> name -> id
+}
+
+// NewDumpTransport serves the dump in dir as if it was the PokeAPI at
+// baseURL.
+func NewDumpTransport(dir, baseURL string) (*DumpTransport, error) {
+	if _, err := os.Stat(filepath.Join(dir, "api", "v2")); err != nil {
+		return nil, fmt.Errorf("%s doesn't look like a PokeAPI data dump: %w", dir, err)
+	}
+	base, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url %s: %w", baseURL, err)
+	}
+	return &DumpTransport{
+		dir:     dir,
+		baseURL: base,
+		indexes: map[string]map[string]string{},
+	}, nil
+}
+
+func (t *DumpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rel, ok := strings.CutPrefix(req.URL.Path, t.baseURL.Path)
+	if !ok || req.URL.Host != t.baseURL.Host {
+		return nil, fmt.Errorf("%s: %w", req.URL, ErrOffline)
+	}
+	segments := strings.Split(strings.Trim(rel, "/"), "/")
+
+	switch len(segments) {
+	case 1:
+		return t.serveList(req, segments[0])
+	case 2:
+		return t.serveResource(req, segments[0], segments[1])
+	}
+	return nil, fmt.Errorf("%s: %w", req.URL, ErrOffline)
+}
+
+// serveList answers a list endpoint by slicing the endpoint's full index
+// according to offset and limit.
+func (t *DumpTransport) serveList(req *http.Request, endpoint string) (*http.Response, error) {
+	data, err := t.readFile(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", req.URL, err)
+	}
+	var list NamedAPIResourceList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("corrupt index for %s in data dump: %w", endpoint, err)
+	}
+
+	query := req.URL.Query()
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 20 // same default as the PokeAPI
+	}
+	offset = min(max(offset, 0), len(list.Results))
+	end := min(offset+limit, len(list.Results))
+
+	page := NamedAPIResourceList{
+		Count:   list.Count,
+		Results: list.Results[offset:end],
+	}
+	if end < len(list.Results) {
+		page.Next = pageURL(req.URL, end, limit)
+	}
+	if offset > 0 {
+		page.Previous = pageURL(req.URL, max(offset-limit, 0), limit)
+	}
+	body, err := json.Marshal(page)
+	if err != nil {
+		return nil, err
+	}
+	return t.respond(req, http.StatusOK, body), nil
+}
+
+func (t *DumpTransport) serveResource(req *http.Request, endpoint, nameOrID string) (*http.Response, error) {
+	id := nameOrID
+	if _, err := strconv.Atoi(nameOrID); err != nil {
+		ids, err := t.index(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", req.URL, err)
+		}
+		var known bool
+		if id, known = ids[nameOrID]; !known {
+			// The index is complete, so the resource doesn't exist at all
+			return t.respond(req, http.StatusNotFound, []byte("Not Found")), nil
+		}
+	}
+
+	data, err := t.readFile(endpoint, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", req.URL, err)
+	}
+	return t.respond(req, http.StatusOK, data), nil
+}
+
+// index maps the names of an endpoint's resources to their ids.
+func (t *DumpTransport) index(endpoint string) (map[string]string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if ids, ok := t.indexes[endpoint]; ok {
+		return ids, nil
+	}
+
+	data, err := t.readFile(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var list NamedAPIResourceList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("corrupt index for %s in data dump: %w", endpoint, err)
+	}
+	ids := make(map[string]string, len(list.Results))
+	for _, result := range list.Results {
+		ids[result.Name] = path.Base(strings.TrimRight(result.URL, "/"))
+	}
+	t.indexes[endpoint] = ids
+	return ids, nil
+}
+
+// readFile reads the index.json below the given path segments and makes
+// the site-relative links in it absolute.
+func (t *DumpTransport) readFile(segments ...string) ([]byte, error) {
+	parts := append([]string{t.dir, "api", "v2"}, segments...)
+	data, err := os.ReadFile(filepath.Join(append(parts, "index.json")...))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrOffline
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bytes.ReplaceAll(data, []byte(`"/api/v2/`), []byte(`"`+t.baseURL.String()+`/`)), nil
+}
+
+func (t *DumpTransport) respond(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func pageURL(u *url.URL, offset, limit int) string {
+	page := *u
+	query := page.Query()
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+	page.RawQuery = query.Encode()
+	return page.String()
+}
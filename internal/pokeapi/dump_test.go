@@ -0,0 +1,113 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeDumpFile(t *testing.T, dir, content string, segments ...string) {
+	t.Helper()
+	path := filepath.Join(append(append([]string{dir, "api", "v2"}, segments...), "index.json")...)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newDumpClient(t *testing.T) *Client {
+	t.Helper()
+	dir := t.TempDir()
+	writeDumpFile(t, dir, `{"count": 3, "next": null, "previous": null, "results": [
+		{"name": "bulbasaur", "url": "/api/v2/pokemon/1/"},
+		{"name": "ivysaur", "url": "/api/v2/pokemon/2/"},
+		{"name": "pikachu", "url": "/api/v2/pokemon/25/"}
+	]}`, "pokemon")
+	writeDumpFile(t, dir, `{"id": 25, "name": "pikachu", "species": {"name": "pikachu", "url": "/api/v2/pokemon-species/25/"}}`, "pokemon", "25")
+
+	transport, err := NewDumpTransport(dir, DefaultBaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient(DefaultBaseURL, time.Second)
+	c.HTTPClient.Transport = transport
+	return c
+}
+
+func TestDumpTransportResources(t *testing.T) {
+	c := newDumpClient(t)
+	ctx := context.Background()
+
+	p, err := c.GetPokemon(ctx, c.URL("pokemon", "pikachu"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.ID != 25 || p.Species.URL != DefaultBaseURL+"/pokemon-species/25/" {
+		t.Errorf("unexpected pokemon %d with species url %s", p.ID, p.Species.URL)
+	}
+
+	_, err = c.GetPokemon(ctx, c.URL("pokemon", "pikachuu"))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a name missing from the index to not exist, got %v", err)
+	}
+
+	_, err = c.GetPokemon(ctx, c.URL("pokemon", "ivysaur"))
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("expected a resource missing from the dump to be unavailable offline, got %v", err)
+	}
+
+	_, err = c.GetPokemonSpecies(ctx, p.Species.URL)
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("expected an endpoint missing from the dump to be unavailable offline, got %v", err)
+	}
+}
+
+func TestDumpTransportPagination(t *testing.T) {
+	c := newDumpClient(t)
+	ctx := context.Background()
+
+	page, err := c.GetResourceList(ctx, c.ListURL("pokemon", 0, 2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Count != 3 || len(page.Results) != 2 || page.Previous != "" || page.Next == "" {
+		t.Fatalf("unexpected first page %+v", page)
+	}
+
+	page, err = c.GetResourceList(ctx, page.Next)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Results) != 1 || page.Results[0].Name != "pikachu" || page.Next != "" || page.Previous == "" {
+		t.Errorf("unexpected second page %+v", page)
+	}
+}
+
+func TestOfflineTransport(t *testing.T) {
+	c := NewClient(DefaultBaseURL, time.Second)
+	calls := 0
+	c.HTTPClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return OfflineTransport{}.RoundTrip(req)
+	})
+
+	_, err := c.GetPokemon(context.Background(), c.URL("pokemon", "pikachu"))
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("expected ErrOffline, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected offline errors to not be retried, got %d calls", calls)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
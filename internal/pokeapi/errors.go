@@ -0,0 +1,83 @@
+package pokeapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors to match with errors.Is. A *StatusError matches the one
+// corresponding to its status code.
+var (
+	ErrNotFound    = errors.New("resource not found")
+	ErrRateLimited = errors.New("rate limited by the PokeAPI")
+)
+
+// StatusError is returned when the PokeAPI answers with a non-2xx status.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	RetryAfter time.Duration // server hint, 0 if there was none
+	Body       []byte        // the start of the body, for diagnostics
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("response from %s failed with status code: %d and\nbody: %s", e.URL, e.StatusCode, e.Body)
+}
+
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// TransportError is returned when no response could be obtained at all,
+// e.g. because the network is down or the connection was reset.
+type TransportError struct {
+	URL string
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("error while trying to get url %s: %v", e.URL, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError is returned when a response body is not the expected JSON.
+type DecodeError struct {
+	URL     string
+	Err     error
+	Snippet []byte // the start of the body, for diagnostics
+}
+
+func (e *DecodeError) Error() string {
+	if len(e.Snippet) == 0 {
+		return fmt.Sprintf("error while trying to decode response from %s: %v", e.URL, e.Err)
+	}
+	return fmt.Sprintf("error while trying to decode response from %s: %v\nbody: %s", e.URL, e.Err, e.Snippet)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// retryable reports whether err is a transient failure worth another try.
+func retryable(err error) bool {
+	if errors.Is(err, ErrOffline) || errors.Is(err, ErrNotRecorded) || errors.Is(err, ErrResponseTooLarge) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return retryableStatus(statusErr.StatusCode)
+	}
+	var transportErr *TransportError
+	return errors.As(err, &transportErr)
+}
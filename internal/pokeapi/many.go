@@ -0,0 +1,84 @@
+package pokeapi
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultWorkers is how many requests GetMany has in flight at once if
+// it is not told otherwise. The rate limiter still applies on top.
+const DefaultWorkers = 8
+
+// Result is the outcome of fetching one URL of a GetMany.
+type Result[T any] struct {
+	URL   string
+	Value T
+	Err   error
+}
+
+// GetMany fetches every url like Get, through the cache and the rate
+// limiter, with at most workers requests in flight. The results are in
+// the order of urls and each carries its own error, so one missing
+// resource doesn't spoil the rest, e.g.
+//
+//	urls := c.URLs("pokemon", "pikachu", "raichu")
+//	for _, r := range GetMany[Pokemon](ctx, c, urls, 0) {
+//		if r.Err != nil {
+//			continue
+//		}
+//		fmt.Println(r.Value.Name)
+//	}
+//
+// Once ctx is done, the URLs not fetched yet fail with its error.
+func GetMany[T any](ctx context.Context, c *Client, urls []string, workers int) []Result[T] {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	results := make([]Result[T], len(urls))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(urls)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				v, err := Get[T](ctx, c, urls[i])
+				results[i] = Result[T]{URL: urls[i], Value: v, Err: err}
+			}
+		}()
+	}
+
+	for i, url := range urls {
+		if ctx.Err() != nil {
+			results[i] = Result[T]{URL: url, Err: ctx.Err()}
+			continue
+		}
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			results[i] = Result[T]{URL: url, Err: ctx.Err()}
+		}
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+// URLs builds the URLs of the named resources of an endpoint, e.g. for
+// GetMany.
+func (c *Client) URLs(endpoint string, names ...string) []string {
+	urls := make([]string, len(names))
+	for i, name := range names {
+		urls[i] = c.URL(endpoint, name)
+	}
+	return urls
+}
+
+// LinkURLs returns the URLs links point to, e.g. for GetMany.
+func LinkURLs[L Link](links []L) []string {
+	urls := make([]string, len(links))
+	for i, link := range links {
+		urls[i] = link.ResourceURL()
+	}
+	return urls
+}
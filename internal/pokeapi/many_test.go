@@ -0,0 +1,97 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tobiaspartzsch/pokedex/internal/pokecache"
+)
+
+func TestGetMany(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight, requests := 0, 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		name := strings.TrimPrefix(r.URL.Path, "/pokemon/")
+		if name == "missingno" {
+			http.NotFound(w, r)
+			return
+		}
+		// Slower for earlier names, so results finish out of order
+		time.Sleep(time.Duration(10-len(name)) * time.Millisecond)
+		fmt.Fprintf(w, `{"name": %q}`, name)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	c.Limiter = nil
+	memory := pokecache.New(pokecache.Config{TTL: time.Hour})
+	defer memory.Close()
+	c.Cache = memory
+
+	names := []string{"mew", "abra", "missingno", "pikachu", "eevee", "onix"}
+	results := GetMany[Pokemon](context.Background(), c, c.URLs("pokemon", names...), 2)
+	if len(results) != len(names) {
+		t.Fatalf("expected %d results, got %d", len(names), len(results))
+	}
+	for i, r := range results {
+		if r.URL != c.URL("pokemon", names[i]) {
+			t.Errorf("result %d is for %s, expected %s", i, r.URL, names[i])
+		}
+		if names[i] == "missingno" {
+			if !errors.Is(r.Err, ErrNotFound) {
+				t.Errorf("expected ErrNotFound for missingno, got %v", r.Err)
+			}
+			continue
+		}
+		if r.Err != nil || r.Value.Name != names[i] {
+			t.Errorf("result %d: got %+v, %v, expected %s", i, r.Value, r.Err, names[i])
+		}
+	}
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+
+	before := requests
+	GetMany[Pokemon](context.Background(), c, c.URLs("pokemon", "mew", "abra"), 2)
+	if requests != before {
+		t.Errorf("expected cached Pokemon not to be fetched again, got %d more requests", requests-before)
+	}
+}
+
+func TestGetManyCancelled(t *testing.T) {
+	c := newTestClient("http://127.0.0.1:0")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := GetMany[Pokemon](ctx, c, c.URLs("pokemon", "mew", "abra"), 1)
+	for _, r := range results {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("expected %s to fail with context.Canceled, got %v", r.URL, r.Err)
+		}
+	}
+}
+
+func TestLinkURLs(t *testing.T) {
+	links := []NamedAPIResource{{Name: "a", URL: "https://x/a/"}, {Name: "b", URL: "https://x/b/"}}
+	if got := LinkURLs(links); len(got) != 2 || got[0] != "https://x/a/" || got[1] != "https://x/b/" {
+		t.Errorf("unexpected URLs %v", got)
+	}
+}
@@ -0,0 +1,68 @@
+package pokeapi
+
+import (
+	"context"
+	"iter"
+)
+
+// PageFetcher gets one page of a list endpoint. Client.GetResourceList is
+// one, a wrapper that serves pages from a cache is another.
+type PageFetcher func(ctx context.Context, url string) (NamedAPIResourceList, error)
+
+// Pages lazily ranges over the pages of a list, starting with the page at
+// url and following the next links until the last page. A page is only
+// fetched once the previous one has been consumed. Iteration ends after
+// the first error, which is yielded with an empty page.
+func Pages(ctx context.Context, url string, fetch PageFetcher) iter.Seq2[NamedAPIResourceList, error] {
+	return func(yield func(NamedAPIResourceList, error) bool) {
+		// A copy, so that the sequence can be ranged over again
+		next := url
+		for next != "" {
+			page, err := fetch(ctx, next)
+			if err != nil {
+				yield(NamedAPIResourceList{}, err)
+				return
+			}
+			if !yield(page, nil) {
+				return
+			}
+			next = page.Next
+		}
+	}
+}
+
+// Results ranges over the results on every page of Pages.
+func Results(ctx context.Context, url string, fetch PageFetcher) iter.Seq2[NamedAPIResource, error] {
+	return func(yield func(NamedAPIResource, error) bool) {
+		for page, err := range Pages(ctx, url, fetch) {
+			if err != nil {
+				yield(NamedAPIResource{}, err)
+				return
+			}
+			for _, result := range page.Results {
+				if !yield(result, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Pages ranges over the pages of a list endpoint such as "pokemon" or
+// "move", limit results per page, starting at offset.
+func (c *Client) Pages(ctx context.Context, endpoint string, offset, limit int) iter.Seq2[NamedAPIResourceList, error] {
+	return Pages(ctx, c.ListURL(endpoint, offset, limit), c.GetResourceList)
+}
+
+// All ranges over every resource of a list endpoint from offset on,
+// fetching limit of them per request, e.g.
+//
+//	for area, err := range c.All(ctx, "location-area", 0, 100) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(area.Name)
+//	}
+func (c *Client) All(ctx context.Context, endpoint string, offset, limit int) iter.Seq2[NamedAPIResource, error] {
+	return Results(ctx, c.ListURL(endpoint, offset, limit), c.GetResourceList)
+}
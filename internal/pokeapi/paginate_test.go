@@ -0,0 +1,118 @@
+package pokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// newListServer serves a list endpoint of the given names that honours
+// offset and limit like the PokeAPI does.
+func newListServer(t *testing.T, names []string, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		end := min(offset+limit, len(names))
+		page := NamedAPIResourceList{Count: len(names)}
+		for _, name := range names[offset:end] {
+			page.Results = append(page.Results, NamedAPIResource{Name: name, URL: server.URL + "/pokemon/" + name})
+		}
+		if end < len(names) {
+			page.Next = server.URL + r.URL.Path + "?offset=" + strconv.Itoa(end) + "&limit=" + strconv.Itoa(limit)
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAllFollowsNextLinks(t *testing.T) {
+	names := []string{"bulbasaur", "ivysaur", "venusaur", "charmander", "charmeleon"}
+	var requests atomic.Int32
+	server := newListServer(t, names, &requests)
+	c := newTestClient(server.URL)
+
+	var got []string
+	for resource, err := range c.All(context.Background(), "pokemon", 1, 2) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, resource.Name)
+	}
+	if len(got) != 4 || got[0] != "ivysaur" || got[3] != "charmeleon" {
+		t.Errorf("expected everything from offset 1 on, got %v", got)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("expected 2 pages to be fetched, got %d", requests.Load())
+	}
+}
+
+func TestAllIsLazy(t *testing.T) {
+	names := []string{"bulbasaur", "ivysaur", "venusaur", "charmander", "charmeleon"}
+	var requests atomic.Int32
+	server := newListServer(t, names, &requests)
+	c := newTestClient(server.URL)
+
+	for resource := range c.All(context.Background(), "pokemon", 0, 2) {
+		if resource.Name == "ivysaur" {
+			break
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected only the first page to be fetched, got %d requests", requests.Load())
+	}
+}
+
+func TestPagesStopsAtFirstError(t *testing.T) {
+	failure := errors.New("boom")
+	calls := 0
+	fetch := func(ctx context.Context, url string) (NamedAPIResourceList, error) {
+		calls++
+		if calls == 2 {
+			return NamedAPIResourceList{}, failure
+		}
+		return NamedAPIResourceList{Next: "next", Results: []NamedAPIResource{{Name: "x"}}}, nil
+	}
+
+	var errs []error
+	pages := 0
+	for _, err := range Pages(context.Background(), "first", fetch) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		pages++
+	}
+	if pages != 1 || len(errs) != 1 || !errors.Is(errs[0], failure) {
+		t.Errorf("expected one page and then the error, got %d pages and %v", pages, errs)
+	}
+}
+
+func TestPagesCanBeRangedTwice(t *testing.T) {
+	names := []string{"bulbasaur", "ivysaur", "venusaur", "charmander", "charmeleon"}
+	var requests atomic.Int32
+	server := newListServer(t, names, &requests)
+	c := newTestClient(server.URL)
+
+	pages := c.Pages(context.Background(), "pokemon", 0, 2)
+	for i := range 2 {
+		count := 0
+		for _, err := range pages {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			count++
+		}
+		if count != 3 {
+			t.Errorf("range %d: expected 3 pages, got %d", i+1, count)
+		}
+	}
+}
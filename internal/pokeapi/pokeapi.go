@@ -1,74 +1,352 @@
 package pokeapi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tobiaspartzsch/pokedex/internal/pokecache"
+)
+
+const (
+	DefaultBaseURL   = "https://pokeapi.co/api/v2"
+	DefaultUserAgent = "pokedex-cli (+https://github.com/TobiasPartzsch/pokedex)"
+	DefaultTimeout   = 10 * time.Second
 )
 
+// DefaultPageSize is the page size the PokeAPI uses for list endpoints
+// without a limit.
+const DefaultPageSize = 20
+
+// Config is a position in a paginated list: the page at Offset, Limit
+// results long, out of Count results in total.
 type Config struct {
-	Next     string
-	Previous string
+	Offset int
+	Limit  int  // results per page, 0 means DefaultPageSize
+	Count  int  // total number of results, only known once a page was fetched
+	Shown  bool // whether a page has been shown yet, before that Offset is meaningless
 }
 
-func GetLocationAreas(url string) (LocationAreas, error) {
-	las := LocationAreas{}
-	err := fetchAndUnmarshall(url, &las)
-	if err != nil {
-		return LocationAreas{}, err
+// PageSize returns Limit or DefaultPageSize if it is not set.
+func (c Config) PageSize() int {
+	if c.Limit <= 0 {
+		return DefaultPageSize
 	}
-	return las, nil
+	return c.Limit
 }
 
-func GetLocationAreaDetails(url string) (LocationArea, error) {
-	la := LocationArea{}
-	err := fetchAndUnmarshall(url, &la)
-	if err != nil {
-		return LocationArea{}, err
+// Page returns the 1-based number of the page at Offset.
+func (c Config) Page() int {
+	return c.Offset/c.PageSize() + 1
+}
+
+// Pages returns the number of pages, at least 1.
+func (c Config) Pages() int {
+	return max(1, (c.Count+c.PageSize()-1)/c.PageSize())
+}
+
+// Client talks to a PokeAPI instance. The zero value is not usable, create
+// one with NewClient.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	UserAgent  string
+	Retry      RetryPolicy
+	Limiter    *RateLimiter    // shared by every request, nil disables throttling
+	Cache      pokecache.Store // raw response bodies by URL, nil disables caching
+	Revalidate bool            // refresh stale cache entries in the background
+	// MaxResponseBytes caps the size of a response body, 0 or less
+	// disables the limit. Longer responses fail with ErrResponseTooLarge.
+	MaxResponseBytes int64
+	inflight         pokecache.Group // coalesces concurrent fetches of one URL
+}
+
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: timeout},
+		UserAgent:  DefaultUserAgent,
+		Retry:      DefaultRetryPolicy,
+		Limiter:    NewRateLimiter(DefaultRequestsPerSecond, DefaultBurst),
+		Revalidate: true,
+
+		MaxResponseBytes: DefaultMaxResponseBytes,
 	}
-	return la, nil
 }
 
-func GetPokemon(url string) (Pokemon, error) {
-	p := Pokemon{}
-	err := fetchAndUnmarshall(url, &p)
-	if err != nil {
-		return Pokemon{}, err
+// URL builds the URL of an endpoint or resource below the base URL,
+// e.g. URL("pokemon", "pikachu").
+func (c *Client) URL(parts ...string) string {
+	var sb strings.Builder
+	sb.WriteString(c.BaseURL)
+	for _, part := range parts {
+		sb.WriteByte('/')
+		sb.WriteString(url.PathEscape(part))
+	}
+	return sb.String()
+}
+
+// ListURL builds the URL of one page of a list endpoint.
+func (c *Client) ListURL(endpoint string, offset, limit int) string {
+	query := url.Values{}
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+	return c.URL(endpoint) + "?" + query.Encode()
+}
+
+func (c *Client) GetResourceList(ctx context.Context, url string) (NamedAPIResourceList, error) {
+	return Get[NamedAPIResourceList](ctx, c, url)
+}
+
+func (c *Client) GetLocationAreas(ctx context.Context, url string) (LocationAreas, error) {
+	return Get[LocationAreas](ctx, c, url)
+}
+
+func (c *Client) GetLocationAreaDetails(ctx context.Context, url string) (LocationArea, error) {
+	return Get[LocationArea](ctx, c, url)
+}
+
+func (c *Client) GetPokemon(ctx context.Context, url string) (Pokemon, error) {
+	return Get[Pokemon](ctx, c, url)
+}
+
+// GetPokemonSummary gets the Pokemon at url, decoding only its summary.
+// It shares the cache entry with GetPokemon.
+func (c *Client) GetPokemonSummary(ctx context.Context, url string) (PokemonSummary, error) {
+	return Get[PokemonSummary](ctx, c, url)
+}
+
+func (c *Client) GetPokemonSpecies(ctx context.Context, url string) (PokemonSpecies, error) {
+	return Get[PokemonSpecies](ctx, c, url)
+}
+
+func (c *Client) GetLocation(ctx context.Context, url string) (Location, error) {
+	return Get[Location](ctx, c, url)
+}
+
+func (c *Client) GetRegion(ctx context.Context, url string) (Region, error) {
+	return Get[Region](ctx, c, url)
+}
+
+func (c *Client) GetGeneration(ctx context.Context, url string) (Generation, error) {
+	return Get[Generation](ctx, c, url)
+}
+
+func (c *Client) GetVersion(ctx context.Context, url string) (Version, error) {
+	return Get[Version](ctx, c, url)
+}
+
+func (c *Client) GetVersionGroup(ctx context.Context, url string) (VersionGroup, error) {
+	return Get[VersionGroup](ctx, c, url)
+}
+
+func (c *Client) GetEncounterMethod(ctx context.Context, url string) (EncounterMethod, error) {
+	return Get[EncounterMethod](ctx, c, url)
+}
+
+func (c *Client) GetMove(ctx context.Context, url string) (Move, error) {
+	return Get[Move](ctx, c, url)
+}
+
+func (c *Client) GetAbility(ctx context.Context, url string) (Ability, error) {
+	return Get[Ability](ctx, c, url)
+}
+
+func (c *Client) GetType(ctx context.Context, url string) (Type, error) {
+	return Get[Type](ctx, c, url)
+}
+
+func (c *Client) GetItem(ctx context.Context, url string) (Item, error) {
+	return Get[Item](ctx, c, url)
+}
+
+func (c *Client) GetBerry(ctx context.Context, url string) (Berry, error) {
+	return Get[Berry](ctx, c, url)
+}
+
+func (c *Client) GetEvolutionChain(ctx context.Context, url string) (EvolutionChain, error) {
+	return Get[EvolutionChain](ctx, c, url)
+}
+
+func (c *Client) GetNature(ctx context.Context, url string) (Nature, error) {
+	return Get[Nature](ctx, c, url)
+}
+
+func (c *Client) GetMachine(ctx context.Context, url string) (Machine, error) {
+	return Get[Machine](ctx, c, url)
+}
+
+// Validators are the HTTP cache validators of a response. Sending them
+// back lets the server answer with a cheap 304 Not Modified.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// RawResponse is an undecoded PokeAPI response.
+type RawResponse struct {
+	Body        []byte // empty if NotModified
+	Validators  Validators
+	NotModified bool
+}
+
+// FetchRaw gets the undecoded body of url, bypassing the cache. If v is
+// not empty, the request is conditional and an unchanged resource is
+// reported through NotModified.
+func (c *Client) FetchRaw(ctx context.Context, url string, v Validators) (RawResponse, error) {
+	return c.fetch(ctx, url, v, nil)
+}
+
+// Get fetches url, through the client's cache, and decodes the response
+// into a T, e.g. Get[Pokemon](ctx, c, c.URL("pokemon", "pikachu")).
+func Get[T any](ctx context.Context, c *Client, url string) (T, error) {
+	var v T
+	if err := c.get(ctx, url, &v); err != nil {
+		var zero T
+		return zero, err
 	}
-	return p, nil
+	return v, nil
 }
 
-func GetPokemonSpecies(url string) (PokemonSpecies, error) {
-	p := PokemonSpecies{}
-	err := fetchAndUnmarshall(url, &p)
+// get fetches url and decodes it into target. Without a cache there is
+// nothing to keep the body for, so it is decoded as it streams in.
+func (c *Client) get(ctx context.Context, url string, target any) error {
+	if c.Cache == nil {
+		_, err := c.fetch(ctx, url, Validators{}, target)
+		return err
+	}
+	body, err := c.fetchCached(ctx, url)
 	if err != nil {
-		return PokemonSpecies{}, err
+		return err
+	}
+	return c.decode(url, body, target)
+}
+
+// Decode decodes a response body fetched from url into a T. It is the
+// decoding half of Get, for bodies that come from elsewhere.
+func Decode[T any](url string, body []byte) (T, error) {
+	var v T
+	if err := decodeInto(url, body, &v); err != nil {
+		var zero T
+		return zero, err
 	}
-	return p, nil
+	return v, nil
 }
 
-func fetchAndUnmarshall(url string, target any) error {
-	res, err := http.Get(url)
+func decodeInto(url string, body []byte, target any) error {
+	if err := json.Unmarshal(body, target); err != nil {
+		return &DecodeError{URL: url, Err: err, Snippet: body[:min(len(body), snippetSize)]}
+	}
+	return nil
+}
+
+// fetch gets url, retrying transient failures according to the client's
+// RetryPolicy. With a target, the body is decoded into it instead of
+// being returned.
+func (c *Client) fetch(ctx context.Context, url string, v Validators, target any) (RawResponse, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := c.fetchOnce(ctx, url, v, target)
+		if err == nil {
+			return res, nil
+		}
+		if !retryable(err) || attempt >= c.Retry.MaxAttempts || ctx.Err() != nil {
+			return RawResponse{}, err
+		}
+		var retryAfter time.Duration
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			retryAfter = statusErr.RetryAfter
+		}
+		wait, ok := c.Retry.wait(attempt, retryAfter)
+		if !ok {
+			return RawResponse{}, err
+		}
+		if sleepErr := sleep(ctx, wait); sleepErr != nil {
+			return RawResponse{}, fmt.Errorf("%w while waiting to retry: %w", sleepErr, err)
+		}
+	}
+}
+
+func (c *Client) fetchOnce(ctx context.Context, url string, v Validators, target any) (RawResponse, error) {
+	if err := c.Limiter.Wait(ctx); err != nil {
+		return RawResponse{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("error while trying to get url %s: %w", url, err)
+		return RawResponse{}, fmt.Errorf("error while trying to build request for url %s: %w", url, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return RawResponse{}, &TransportError{URL: url, Err: err}
 	}
 	// Always close the response body when you're done with it!
 	defer res.Body.Close()
+	body := &bodyReader{r: res.Body, max: c.MaxResponseBytes}
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error while trying to read response body %v: %w", res.Body, err)
+	validators := Validators{
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+	}
+	// A 304 to a request without validators has no body to use, it is
+	// reported as a StatusError below
+	conditional := v.ETag != "" || v.LastModified != ""
+	if res.StatusCode == http.StatusNotModified && conditional {
+		return RawResponse{Validators: validators, NotModified: true}, nil
 	}
 
 	if res.StatusCode > 299 {
-		return fmt.Errorf("response failed with status code: %d and\nbody: %s", res.StatusCode, body)
+		return RawResponse{}, &StatusError{
+			URL:        url,
+			StatusCode: res.StatusCode,
+			RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), time.Now()),
+			Body:       body.snippet(),
+		}
 	}
 
-	err = json.Unmarshal(body, target)
-	if err != nil {
-		return fmt.Errorf("error while trying to unmarshal body into target: %w", err)
+	// An HTML page from a proxy or captive portal is not worth decoding
+	if ct := res.Header.Get("Content-Type"); !jsonContentType(ct) {
+		return RawResponse{}, &DecodeError{
+			URL:     url,
+			Err:     fmt.Errorf("%w %q", ErrUnexpectedContentType, ct),
+			Snippet: body.snippet(),
+		}
 	}
 
-	return nil
+	if target != nil {
+		if err := json.NewDecoder(body).Decode(target); err != nil {
+			return RawResponse{}, body.wrap(url, err)
+		}
+		return RawResponse{Validators: validators}, nil
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return RawResponse{}, body.wrap(url, err)
+	}
+	return RawResponse{Body: data, Validators: validators}, nil
 }
@@ -0,0 +1,263 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(url string) *Client {
+	c := NewClient(url, time.Second)
+	c.Retry = RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+	}
+	return c
+}
+
+func TestFetchRetriesTransientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if calls.Load() < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id": 25, "name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	p, err := c.GetPokemon(context.Background(), c.URL("pokemon", "pikachu"))
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if p.Name != "pikachu" {
+		t.Errorf("expected pikachu, got %q", p.Name)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 calls, got %d", calls.Load())
+	}
+}
+
+func TestFetchDoesNotRetryNotFound(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	_, err := c.GetPokemon(context.Background(), c.URL("pokemon", "pikachuu"))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 call, got %d", calls.Load())
+	}
+}
+
+func TestFetchGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	_, err := c.GetPokemon(context.Background(), c.URL("pokemon", "pikachu"))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if int(calls.Load()) != c.Retry.MaxAttempts {
+		t.Errorf("expected %d calls, got %d", c.Retry.MaxAttempts, calls.Load())
+	}
+}
+
+func TestFetchGivesUpOnLongRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	_, err := c.GetPokemon(context.Background(), c.URL("pokemon", "pikachu"))
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected no retry before the server allows it, got %d calls", calls.Load())
+	}
+}
+
+func TestFetchCancelledWhileWaiting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	c.Retry.MaxDelay = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.GetPokemon(ctx, c.URL("pokemon", "pikachu"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to be reported, got %v", err)
+	}
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the failed attempt to be kept, got %v", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{value: "", expected: 0},
+		{value: "3", expected: 3 * time.Second},
+		{value: "-1", expected: 0},
+		{value: "soon", expected: 0},
+		{value: now.Add(90 * time.Second).Format(http.TimeFormat), expected: 90 * time.Second},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), expected: 0},
+	}
+	for _, c := range cases {
+		if actual := parseRetryAfter(c.value, now); actual != c.expected {
+			t.Errorf("parseRetryAfter(%q) = %v, expected %v", c.value, actual, c.expected)
+		}
+	}
+}
+
+func TestBackoffIsBounded(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 10, BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	for attempt := 1; attempt <= 10; attempt++ {
+		if d := p.backoff(attempt); d < 0 || d > p.MaxDelay {
+			t.Errorf("backoff(%d) = %v, expected within [0, %v]", attempt, d, p.MaxDelay)
+		}
+	}
+}
+
+func TestBackoffWithoutMaxDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 100, BaseDelay: 100 * time.Millisecond}
+	grew := false
+	for range 100 {
+		if p.backoff(5) > p.BaseDelay {
+			grew = true
+			break
+		}
+	}
+	if !grew {
+		t.Errorf("expected the backoff to keep doubling without a MaxDelay")
+	}
+	for attempt := 1; attempt <= 100; attempt++ {
+		if d := p.backoff(attempt); d < 0 {
+			t.Errorf("backoff(%d) = %v, expected it not to overflow", attempt, d)
+		}
+	}
+	if d, ok := p.wait(1, time.Hour); !ok || d != time.Hour {
+		t.Errorf("expected any Retry-After to be honored without a MaxDelay, got %v, %v", d, ok)
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pokemon/missingno":
+			http.NotFound(w, r)
+		case "/pokemon/busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`{not json`))
+		}
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	c.Retry.MaxAttempts = 1
+	ctx := context.Background()
+
+	_, err := c.GetPokemon(ctx, c.URL("pokemon", "missingno"))
+	var statusErr *StatusError
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &statusErr) {
+		t.Fatalf("expected a not found StatusError, got %v", err)
+	}
+	if statusErr.StatusCode != http.StatusNotFound || statusErr.URL != c.URL("pokemon", "missingno") {
+		t.Errorf("unexpected StatusError %+v", statusErr)
+	}
+
+	_, err = c.GetPokemon(ctx, c.URL("pokemon", "busy"))
+	if !errors.Is(err, ErrRateLimited) || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+
+	_, err = c.GetPokemon(ctx, c.URL("pokemon", "garbled"))
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Errorf("expected a DecodeError, got %v", err)
+	}
+
+	server.Close()
+	_, err = c.GetPokemon(ctx, c.URL("pokemon", "pikachu"))
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) {
+		t.Errorf("expected a TransportError, got %v", err)
+	}
+}
+
+func TestFetchRawConditional(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	url := c.URL("pokemon", "pikachu")
+	res, err := c.FetchRaw(context.Background(), url, Validators{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.NotModified || res.Validators.ETag != `"v1"` || len(res.Body) == 0 {
+		t.Fatalf("unexpected response %+v", res)
+	}
+
+	res, err = c.FetchRaw(context.Background(), url, res.Validators)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.NotModified || len(res.Body) != 0 {
+		t.Errorf("expected a 304 without body, got %+v", res)
+	}
+}
+
+func TestUnconditionalNotModifiedIsAnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	_, err := c.FetchRaw(context.Background(), c.URL("pokemon", "pikachu"), Validators{})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotModified {
+		t.Errorf("expected a StatusError for a 304 to a plain GET, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package pokeapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRequestsPerSecond = 10
+	DefaultBurst             = 20
+)
+
+// RateLimiter is a token bucket shared by all requests of a Client. A nil
+// *RateLimiter does not limit at all.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64 // bucket capacity
+	tokens float64 // may become negative while callers wait for reserved tokens
+	last   time.Time
+	now    func() time.Time
+	stats  RateLimiterStats
+}
+
+// RateLimiterStats describes how much a RateLimiter has throttled so far.
+type RateLimiterStats struct {
+	Requests  int64         // requests that were let through
+	Throttled int64         // requests that had to wait for a token
+	Cancelled int64         // requests whose context ended while waiting
+	Waited    time.Duration // total time spent waiting
+}
+
+// NewRateLimiter allows perSecond requests per second on average and up to
+// burst requests at once.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return ctx.Err()
+	}
+
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, delay); err != nil {
+		l.mu.Lock()
+		l.tokens++ // hand the reserved token back
+		l.stats.Requests--
+		l.stats.Throttled--
+		l.stats.Cancelled++
+		l.mu.Unlock()
+		return err
+	}
+
+	l.mu.Lock()
+	l.stats.Waited += delay
+	l.mu.Unlock()
+	return nil
+}
+
+// reserve takes a token and returns how long to wait until it is valid.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	l.tokens--
+	l.stats.Requests++
+	if l.tokens >= 0 {
+		return 0
+	}
+	l.stats.Throttled++
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+func (l *RateLimiter) Stats() RateLimiterStats {
+	if l == nil {
+		return RateLimiterStats{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// Limit returns the configured requests per second and burst size.
+func (l *RateLimiter) Limit() (perSecond float64, burst int) {
+	if l == nil {
+		return 0, 0
+	}
+	return l.rate, int(l.burst)
+}
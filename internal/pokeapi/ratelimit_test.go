@@ -0,0 +1,60 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurstThenThrottle(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(10, 2)
+	l.now = func() time.Time { return now }
+	l.last = now
+
+	if d := l.reserve(); d != 0 {
+		t.Errorf("expected first request of the burst to pass, waited %v", d)
+	}
+	if d := l.reserve(); d != 0 {
+		t.Errorf("expected second request of the burst to pass, waited %v", d)
+	}
+	if d := l.reserve(); d != 100*time.Millisecond {
+		t.Errorf("expected third request to wait 100ms, waited %v", d)
+	}
+
+	now = now.Add(time.Second)
+	if d := l.reserve(); d != 0 {
+		t.Errorf("expected bucket to refill after a second, waited %v", d)
+	}
+
+	stats := l.Stats()
+	if stats.Requests != 4 || stats.Throttled != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	l := NewRateLimiter(0.001, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("expected the burst token to be available, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	stats := l.Stats()
+	if stats.Requests != 1 || stats.Cancelled != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestNilRateLimiterDoesNotLimit(t *testing.T) {
+	var l *RateLimiter
+	if err := l.Wait(context.Background()); err != nil {
+		t.Errorf("expected nil limiter to never block, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package pokeapi
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how often and how patiently failed requests are
+// retried. Only transport errors and transient status codes (408, 429 and
+// 5xx gateway/availability errors) are retried, a 404 fails right away.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first one, <= 1 disables retries
+	BaseDelay   time.Duration // upper bound of the first backoff, doubled for every further attempt
+	MaxDelay    time.Duration // upper bound of any single wait, a longer Retry-After gives up, <= 0 means unbounded
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// backoff returns a jittered delay before retry number attempt (starting
+// at 1), drawn uniformly from [0, min(MaxDelay, BaseDelay*2^(attempt-1))].
+// A MaxDelay of 0 or less means no upper bound.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		if p.MaxDelay > 0 && ceiling >= p.MaxDelay {
+			break
+		}
+		// Without a MaxDelay the doubling only stops short of overflowing
+		if ceiling > math.MaxInt64/2 {
+			break
+		}
+		ceiling *= 2
+	}
+	if p.MaxDelay > 0 && ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// wait honors a server provided Retry-After if there is one and falls back
+// to the jittered backoff otherwise. A Retry-After beyond MaxDelay is not
+// waited for and not cut short either, ok is false and the request should
+// fail.
+func (p RetryPolicy) wait(attempt int, retryAfter time.Duration) (d time.Duration, ok bool) {
+	if retryAfter <= 0 {
+		return p.backoff(attempt), true
+	}
+	if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+		return 0, false
+	}
+	return retryAfter, true
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter understands both forms of the Retry-After header:
+// a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -2,14 +2,94 @@ package pokeapi
 
 import "fmt"
 
-type LocationAreas struct {
-	Count    int    `json:"count"`
-	Next     string `json:"next"`
-	Previous string `json:"previous"`
-	Results  []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"results"`
+// LocationAreas is one page of the location-area list endpoint.
+type LocationAreas = NamedAPIResourceList
+
+// NamedAPIResourceList is one page of any paginated list endpoint.
+type NamedAPIResourceList struct {
+	Count    int                `json:"count"`
+	Next     string             `json:"next"`
+	Previous string             `json:"previous"`
+	Results  []NamedAPIResource `json:"results"`
+}
+
+// NamedAPIResource is a link to another resource by name.
+type NamedAPIResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// APIResource is a link to another resource that has no name.
+type APIResource struct {
+	URL string `json:"url"`
+}
+
+// Name is the name of a resource in one language.
+type Name struct {
+	Name     string           `json:"name"`
+	Language NamedAPIResource `json:"language"`
+}
+
+type Description struct {
+	Description string           `json:"description"`
+	Language    NamedAPIResource `json:"language"`
+}
+
+type Effect struct {
+	Effect   string           `json:"effect"`
+	Language NamedAPIResource `json:"language"`
+}
+
+type VerboseEffect struct {
+	Effect      string           `json:"effect"`
+	ShortEffect string           `json:"short_effect"`
+	Language    NamedAPIResource `json:"language"`
+}
+
+// FlavorText is the in-game description of a resource. Depending on the
+// resource, it is given per Version or per VersionGroup.
+type FlavorText struct {
+	FlavorText   string           `json:"flavor_text"`
+	Language     NamedAPIResource `json:"language"`
+	Version      NamedAPIResource `json:"version"`
+	VersionGroup NamedAPIResource `json:"version_group"`
+}
+
+// VersionGroupFlavorText is the flavor text of items, which calls it text.
+type VersionGroupFlavorText struct {
+	Text         string           `json:"text"`
+	Language     NamedAPIResource `json:"language"`
+	VersionGroup NamedAPIResource `json:"version_group"`
+}
+
+type VersionGameIndex struct {
+	GameIndex int              `json:"game_index"`
+	Version   NamedAPIResource `json:"version"`
+}
+
+type GenerationGameIndex struct {
+	GameIndex  int              `json:"game_index"`
+	Generation NamedAPIResource `json:"generation"`
+}
+
+type MachineVersionDetail struct {
+	Machine      APIResource      `json:"machine"`
+	VersionGroup NamedAPIResource `json:"version_group"`
+}
+
+// VersionEncounterDetail lists the ways to encounter a Pokemon in a version.
+type VersionEncounterDetail struct {
+	Version          NamedAPIResource `json:"version"`
+	MaxChance        int              `json:"max_chance"`
+	EncounterDetails []Encounter      `json:"encounter_details"`
+}
+
+type Encounter struct {
+	MinLevel        int                `json:"min_level"`
+	MaxLevel        int                `json:"max_level"`
+	ConditionValues []NamedAPIResource `json:"condition_values"`
+	Chance          int                `json:"chance"`
+	Method          NamedAPIResource   `json:"method"`
 }
 
 type LocationArea struct {
@@ -17,52 +97,286 @@ type LocationArea struct {
 	Name                 string `json:"name"`
 	GameIndex            int    `json:"game_index"`
 	EncounterMethodRates []struct {
-		EncounterMethod struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"encounter_method"`
-		VersionDetails []struct {
-			Rate    int `json:"rate"`
-			Version struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
+		EncounterMethod NamedAPIResource `json:"encounter_method"`
+		VersionDetails  []struct {
+			Rate    int              `json:"rate"`
+			Version NamedAPIResource `json:"version"`
 		} `json:"version_details"`
 	} `json:"encounter_method_rates"`
-	Location struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"location"`
-	Names []struct {
-		Name     string `json:"name"`
-		Language struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"language"`
-	} `json:"names"`
+	Location          NamedAPIResource `json:"location"`
+	Names             []Name           `json:"names"`
 	PokemonEncounters []struct {
-		Pokemon struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"pokemon"`
+		Pokemon        NamedAPIResource         `json:"pokemon"`
+		VersionDetails []VersionEncounterDetail `json:"version_details"`
+	} `json:"pokemon_encounters"`
+}
+
+type Location struct {
+	ID          int                   `json:"id"`
+	Name        string                `json:"name"`
+	Region      NamedAPIResource      `json:"region"`
+	Names       []Name                `json:"names"`
+	GameIndices []GenerationGameIndex `json:"game_indices"`
+	Areas       []NamedAPIResource    `json:"areas"`
+}
+
+type Region struct {
+	ID             int                `json:"id"`
+	Name           string             `json:"name"`
+	Locations      []NamedAPIResource `json:"locations"`
+	MainGeneration NamedAPIResource   `json:"main_generation"`
+	Names          []Name             `json:"names"`
+	Pokedexes      []NamedAPIResource `json:"pokedexes"`
+	VersionGroups  []NamedAPIResource `json:"version_groups"`
+}
+
+type Generation struct {
+	ID             int                `json:"id"`
+	Name           string             `json:"name"`
+	Abilities      []NamedAPIResource `json:"abilities"`
+	MainRegion     NamedAPIResource   `json:"main_region"`
+	Moves          []NamedAPIResource `json:"moves"`
+	Names          []Name             `json:"names"`
+	PokemonSpecies []NamedAPIResource `json:"pokemon_species"`
+	Types          []NamedAPIResource `json:"types"`
+	VersionGroups  []NamedAPIResource `json:"version_groups"`
+}
+
+type Version struct {
+	ID           int              `json:"id"`
+	Name         string           `json:"name"`
+	Names        []Name           `json:"names"`
+	VersionGroup NamedAPIResource `json:"version_group"`
+}
+
+type VersionGroup struct {
+	ID               int                `json:"id"`
+	Name             string             `json:"name"`
+	Order            int                `json:"order"`
+	Generation       NamedAPIResource   `json:"generation"`
+	MoveLearnMethods []NamedAPIResource `json:"move_learn_methods"`
+	Pokedexes        []NamedAPIResource `json:"pokedexes"`
+	Regions          []NamedAPIResource `json:"regions"`
+	Versions         []NamedAPIResource `json:"versions"`
+}
+
+type EncounterMethod struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Order int    `json:"order"`
+	Names []Name `json:"names"`
+}
+
+type Move struct {
+	ID           int              `json:"id"`
+	Name         string           `json:"name"`
+	Accuracy     int              `json:"accuracy"`
+	EffectChance int              `json:"effect_chance"`
+	PP           int              `json:"pp"`
+	Priority     int              `json:"priority"`
+	Power        int              `json:"power"`
+	DamageClass  NamedAPIResource `json:"damage_class"`
+	Meta         struct {
+		Ailment       NamedAPIResource `json:"ailment"`
+		Category      NamedAPIResource `json:"category"`
+		MinHits       int              `json:"min_hits"`
+		MaxHits       int              `json:"max_hits"`
+		MinTurns      int              `json:"min_turns"`
+		MaxTurns      int              `json:"max_turns"`
+		Drain         int              `json:"drain"`
+		Healing       int              `json:"healing"`
+		CritRate      int              `json:"crit_rate"`
+		AilmentChance int              `json:"ailment_chance"`
+		FlinchChance  int              `json:"flinch_chance"`
+		StatChance    int              `json:"stat_chance"`
+	} `json:"meta"`
+	EffectEntries     []VerboseEffect        `json:"effect_entries"`
+	FlavorTextEntries []FlavorText           `json:"flavor_text_entries"`
+	Generation        NamedAPIResource       `json:"generation"`
+	LearnedByPokemon  []NamedAPIResource     `json:"learned_by_pokemon"`
+	Machines          []MachineVersionDetail `json:"machines"`
+	Names             []Name                 `json:"names"`
+	StatChanges       []struct {
+		Change int              `json:"change"`
+		Stat   NamedAPIResource `json:"stat"`
+	} `json:"stat_changes"`
+	Target NamedAPIResource `json:"target"`
+	Type   NamedAPIResource `json:"type"`
+}
+
+type Ability struct {
+	ID                int              `json:"id"`
+	Name              string           `json:"name"`
+	IsMainSeries      bool             `json:"is_main_series"`
+	Generation        NamedAPIResource `json:"generation"`
+	Names             []Name           `json:"names"`
+	EffectEntries     []VerboseEffect  `json:"effect_entries"`
+	FlavorTextEntries []FlavorText     `json:"flavor_text_entries"`
+	Pokemon           []struct {
+		IsHidden bool             `json:"is_hidden"`
+		Slot     int              `json:"slot"`
+		Pokemon  NamedAPIResource `json:"pokemon"`
+	} `json:"pokemon"`
+}
+
+type Type struct {
+	ID                  int           `json:"id"`
+	Name                string        `json:"name"`
+	DamageRelations     TypeRelations `json:"damage_relations"`
+	PastDamageRelations []struct {
+		Generation      NamedAPIResource `json:"generation"`
+		DamageRelations TypeRelations    `json:"damage_relations"`
+	} `json:"past_damage_relations"`
+	GameIndices     []GenerationGameIndex `json:"game_indices"`
+	Generation      NamedAPIResource      `json:"generation"`
+	MoveDamageClass NamedAPIResource      `json:"move_damage_class"`
+	Names           []Name                `json:"names"`
+	Pokemon         []struct {
+		Slot    int              `json:"slot"`
+		Pokemon NamedAPIResource `json:"pokemon"`
+	} `json:"pokemon"`
+	Moves []NamedAPIResource `json:"moves"`
+}
+
+// TypeRelations lists the types a type is (not) very effective against
+// and the types that are (not) very effective against it.
+type TypeRelations struct {
+	NoDamageTo       []NamedAPIResource `json:"no_damage_to"`
+	HalfDamageTo     []NamedAPIResource `json:"half_damage_to"`
+	DoubleDamageTo   []NamedAPIResource `json:"double_damage_to"`
+	NoDamageFrom     []NamedAPIResource `json:"no_damage_from"`
+	HalfDamageFrom   []NamedAPIResource `json:"half_damage_from"`
+	DoubleDamageFrom []NamedAPIResource `json:"double_damage_from"`
+}
+
+// DamageFrom returns the damage multiplier of an attack of the given type
+// against this type: 0, 0.5, 1 or 2.
+func (r TypeRelations) DamageFrom(attackType string) float64 {
+	switch {
+	case containsName(r.NoDamageFrom, attackType):
+		return 0
+	case containsName(r.HalfDamageFrom, attackType):
+		return 0.5
+	case containsName(r.DoubleDamageFrom, attackType):
+		return 2
+	}
+	return 1
+}
+
+func containsName(resources []NamedAPIResource, name string) bool {
+	for _, resource := range resources {
+		if resource.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+type Item struct {
+	ID                int                      `json:"id"`
+	Name              string                   `json:"name"`
+	Cost              int                      `json:"cost"`
+	FlingPower        int                      `json:"fling_power"`
+	FlingEffect       NamedAPIResource         `json:"fling_effect"`
+	Attributes        []NamedAPIResource       `json:"attributes"`
+	Category          NamedAPIResource         `json:"category"`
+	EffectEntries     []VerboseEffect          `json:"effect_entries"`
+	FlavorTextEntries []VersionGroupFlavorText `json:"flavor_text_entries"`
+	GameIndices       []GenerationGameIndex    `json:"game_indices"`
+	Names             []Name                   `json:"names"`
+	Sprites           struct {
+		Default string `json:"default"`
+	} `json:"sprites"`
+	HeldByPokemon []struct {
+		Pokemon        NamedAPIResource `json:"pokemon"`
 		VersionDetails []struct {
-			Version struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
-			MaxChance        int `json:"max_chance"`
-			EncounterDetails []struct {
-				MinLevel        int   `json:"min_level"`
-				MaxLevel        int   `json:"max_level"`
-				ConditionValues []any `json:"condition_values"`
-				Chance          int   `json:"chance"`
-				Method          struct {
-					Name string `json:"name"`
-					URL  string `json:"url"`
-				} `json:"method"`
-			} `json:"encounter_details"`
+			Rarity  int              `json:"rarity"`
+			Version NamedAPIResource `json:"version"`
 		} `json:"version_details"`
-	} `json:"pokemon_encounters"`
+	} `json:"held_by_pokemon"`
+	BabyTriggerFor APIResource            `json:"baby_trigger_for"`
+	Machines       []MachineVersionDetail `json:"machines"`
+}
+
+type Berry struct {
+	ID               int              `json:"id"`
+	Name             string           `json:"name"`
+	GrowthTime       int              `json:"growth_time"`
+	MaxHarvest       int              `json:"max_harvest"`
+	NaturalGiftPower int              `json:"natural_gift_power"`
+	Size             int              `json:"size"`
+	Smoothness       int              `json:"smoothness"`
+	SoilDryness      int              `json:"soil_dryness"`
+	Firmness         NamedAPIResource `json:"firmness"`
+	Flavors          []struct {
+		Potency int              `json:"potency"`
+		Flavor  NamedAPIResource `json:"flavor"`
+	} `json:"flavors"`
+	Item            NamedAPIResource `json:"item"`
+	NaturalGiftType NamedAPIResource `json:"natural_gift_type"`
+}
+
+type EvolutionChain struct {
+	ID              int              `json:"id"`
+	BabyTriggerItem NamedAPIResource `json:"baby_trigger_item"`
+	Chain           ChainLink        `json:"chain"`
+}
+
+// ChainLink is one species in an evolution chain and what it evolves into.
+type ChainLink struct {
+	IsBaby           bool              `json:"is_baby"`
+	Species          NamedAPIResource  `json:"species"`
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+}
+
+// EvolutionDetail is one way to evolve into a species. Unset conditions
+// are zero.
+type EvolutionDetail struct {
+	Item                  NamedAPIResource `json:"item"`
+	Trigger               NamedAPIResource `json:"trigger"`
+	Gender                int              `json:"gender"`
+	HeldItem              NamedAPIResource `json:"held_item"`
+	KnownMove             NamedAPIResource `json:"known_move"`
+	KnownMoveType         NamedAPIResource `json:"known_move_type"`
+	Location              NamedAPIResource `json:"location"`
+	MinLevel              int              `json:"min_level"`
+	MinHappiness          int              `json:"min_happiness"`
+	MinBeauty             int              `json:"min_beauty"`
+	MinAffection          int              `json:"min_affection"`
+	NeedsOverworldRain    bool             `json:"needs_overworld_rain"`
+	PartySpecies          NamedAPIResource `json:"party_species"`
+	PartyType             NamedAPIResource `json:"party_type"`
+	RelativePhysicalStats int              `json:"relative_physical_stats"`
+	TimeOfDay             string           `json:"time_of_day"`
+	TradeSpecies          NamedAPIResource `json:"trade_species"`
+	TurnUpsideDown        bool             `json:"turn_upside_down"`
+}
+
+type Nature struct {
+	ID                    int              `json:"id"`
+	Name                  string           `json:"name"`
+	DecreasedStat         NamedAPIResource `json:"decreased_stat"`
+	IncreasedStat         NamedAPIResource `json:"increased_stat"`
+	HatesFlavor           NamedAPIResource `json:"hates_flavor"`
+	LikesFlavor           NamedAPIResource `json:"likes_flavor"`
+	PokeathlonStatChanges []struct {
+		MaxChange      int              `json:"max_change"`
+		PokeathlonStat NamedAPIResource `json:"pokeathlon_stat"`
+	} `json:"pokeathlon_stat_changes"`
+	MoveBattleStylePreferences []struct {
+		LowHPPreference  int              `json:"low_hp_preference"`
+		HighHPPreference int              `json:"high_hp_preference"`
+		MoveBattleStyle  NamedAPIResource `json:"move_battle_style"`
+	} `json:"move_battle_style_preferences"`
+	Names []Name `json:"names"`
+}
+
+type Machine struct {
+	ID           int              `json:"id"`
+	Item         NamedAPIResource `json:"item"`
+	Move         NamedAPIResource `json:"move"`
+	VersionGroup NamedAPIResource `json:"version_group"`
 }
 
 type Pokemon struct {
@@ -74,60 +388,30 @@ type Pokemon struct {
 	Order          int    `json:"order"`
 	Weight         int    `json:"weight"`
 	Abilities      []struct {
-		IsHidden bool `json:"is_hidden"`
-		Slot     int  `json:"slot"`
-		Ability  struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"ability"`
+		IsHidden bool             `json:"is_hidden"`
+		Slot     int              `json:"slot"`
+		Ability  NamedAPIResource `json:"ability"`
 	} `json:"abilities"`
-	Forms []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"forms"`
-	GameIndices []struct {
-		GameIndex int `json:"game_index"`
-		Version   struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"version"`
-	} `json:"game_indices"`
-	HeldItems []struct {
-		Item struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"item"`
+	Forms       []NamedAPIResource `json:"forms"`
+	GameIndices []VersionGameIndex `json:"game_indices"`
+	HeldItems   []struct {
+		Item           NamedAPIResource `json:"item"`
 		VersionDetails []struct {
-			Rarity  int `json:"rarity"`
-			Version struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
+			Rarity  int              `json:"rarity"`
+			Version NamedAPIResource `json:"version"`
 		} `json:"version_details"`
 	} `json:"held_items"`
 	LocationAreaEncounters string `json:"location_area_encounters"`
 	Moves                  []struct {
-		Move struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"move"`
+		Move                NamedAPIResource `json:"move"`
 		VersionGroupDetails []struct {
-			LevelLearnedAt int `json:"level_learned_at"`
-			VersionGroup   struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version_group"`
-			MoveLearnMethod struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"move_learn_method"`
-			Order int `json:"order"`
+			LevelLearnedAt  int              `json:"level_learned_at"`
+			VersionGroup    NamedAPIResource `json:"version_group"`
+			MoveLearnMethod NamedAPIResource `json:"move_learn_method"`
+			Order           int              `json:"order"`
 		} `json:"version_group_details"`
 	} `json:"moves"`
-	Species struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"species"`
+	Species NamedAPIResource `json:"species"`
 	Sprites struct {
 		BackDefault      string `json:"back_default"`
 		BackFemale       any    `json:"back_female"`
@@ -308,137 +592,103 @@ type Pokemon struct {
 		Latest string `json:"latest"`
 		Legacy string `json:"legacy"`
 	} `json:"cries"`
-	Stats []struct {
-		BaseStat int `json:"base_stat"`
-		Effort   int `json:"effort"`
-		Stat     struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"stat"`
-	} `json:"stats"`
-	Types []struct {
-		Slot int `json:"slot"`
-		Type struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"type"`
-	} `json:"types"`
+	Stats     []PokemonStat `json:"stats"`
+	Types     []PokemonType `json:"types"`
 	PastTypes []struct {
-		Generation struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"generation"`
-		Types []struct {
-			Slot int `json:"slot"`
-			Type struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"type"`
-		} `json:"types"`
+		Generation NamedAPIResource `json:"generation"`
+		Types      []PokemonType    `json:"types"`
 	} `json:"past_types"`
 	PastAbilities []struct {
-		Generation struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"generation"`
-		Abilities []struct {
-			Ability  any  `json:"ability"`
-			IsHidden bool `json:"is_hidden"`
-			Slot     int  `json:"slot"`
+		Generation NamedAPIResource `json:"generation"`
+		Abilities  []struct {
+			Ability  NamedAPIResource `json:"ability"`
+			IsHidden bool             `json:"is_hidden"`
+			Slot     int              `json:"slot"`
 		} `json:"abilities"`
 	} `json:"past_abilities"`
 }
 
 type PokemonSpecies struct {
-	ID                   int    `json:"id"`
-	Name                 string `json:"name"`
-	Order                int    `json:"order"`
-	GenderRate           int    `json:"gender_rate"`
-	CaptureRate          int    `json:"capture_rate"`
-	BaseHappiness        int    `json:"base_happiness"`
-	IsBaby               bool   `json:"is_baby"`
-	IsLegendary          bool   `json:"is_legendary"`
-	IsMythical           bool   `json:"is_mythical"`
-	HatchCounter         int    `json:"hatch_counter"`
-	HasGenderDifferences bool   `json:"has_gender_differences"`
-	FormsSwitchable      bool   `json:"forms_switchable"`
-	GrowthRate           struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"growth_rate"`
-	PokedexNumbers []struct {
-		EntryNumber int `json:"entry_number"`
-		Pokedex     struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"pokedex"`
+	ID                   int              `json:"id"`
+	Name                 string           `json:"name"`
+	Order                int              `json:"order"`
+	GenderRate           int              `json:"gender_rate"`
+	CaptureRate          int              `json:"capture_rate"`
+	BaseHappiness        int              `json:"base_happiness"`
+	IsBaby               bool             `json:"is_baby"`
+	IsLegendary          bool             `json:"is_legendary"`
+	IsMythical           bool             `json:"is_mythical"`
+	HatchCounter         int              `json:"hatch_counter"`
+	HasGenderDifferences bool             `json:"has_gender_differences"`
+	FormsSwitchable      bool             `json:"forms_switchable"`
+	GrowthRate           NamedAPIResource `json:"growth_rate"`
+	PokedexNumbers       []struct {
+		EntryNumber int              `json:"entry_number"`
+		Pokedex     NamedAPIResource `json:"pokedex"`
 	} `json:"pokedex_numbers"`
-	EggGroups []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"egg_groups"`
-	Color struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"color"`
-	Shape struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"shape"`
-	EvolvesFromSpecies struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"evolves_from_species"`
-	EvolutionChain struct {
-		URL string `json:"url"`
-	} `json:"evolution_chain"`
-	Habitat    any `json:"habitat"`
-	Generation struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"generation"`
-	Names []struct {
-		Name     string `json:"name"`
-		Language struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"language"`
-	} `json:"names"`
-	FlavorTextEntries []struct {
-		FlavorText string `json:"flavor_text"`
-		Language   struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"language"`
-		Version struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"version"`
-	} `json:"flavor_text_entries"`
-	FormDescriptions []struct {
-		Description string `json:"description"`
-		Language    struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"language"`
-	} `json:"form_descriptions"`
-	Genera []struct {
-		Genus    string `json:"genus"`
-		Language struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"language"`
+	EggGroups          []NamedAPIResource `json:"egg_groups"`
+	Color              NamedAPIResource   `json:"color"`
+	Shape              NamedAPIResource   `json:"shape"`
+	EvolvesFromSpecies NamedAPIResource   `json:"evolves_from_species"`
+	EvolutionChain     APIResource        `json:"evolution_chain"`
+	Habitat            NamedAPIResource   `json:"habitat"`
+	Generation         NamedAPIResource   `json:"generation"`
+	Names              []Name             `json:"names"`
+	FlavorTextEntries  []FlavorText       `json:"flavor_text_entries"`
+	FormDescriptions   []Description      `json:"form_descriptions"`
+	Genera             []struct {
+		Genus    string           `json:"genus"`
+		Language NamedAPIResource `json:"language"`
 	} `json:"genera"`
 	Varieties []struct {
-		IsDefault bool `json:"is_default"`
-		Pokemon   struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"pokemon"`
+		IsDefault bool             `json:"is_default"`
+		Pokemon   NamedAPIResource `json:"pokemon"`
 	} `json:"varieties"`
 }
 
-func (p Pokemon) PrintDetails() {
+// PokemonType is one of the types of a Pokemon, Slot orders them.
+type PokemonType struct {
+	Slot int              `json:"slot"`
+	Type NamedAPIResource `json:"type"`
+}
+
+// PokemonStat is a base stat of a Pokemon, e.g. its speed.
+type PokemonStat struct {
+	BaseStat int              `json:"base_stat"`
+	Effort   int              `json:"effort"`
+	Stat     NamedAPIResource `json:"stat"`
+}
+
+// PokemonSummary is the part of a Pokemon that is listed and kept in the
+// Pokedex. Decoding a response into it skips the sprites, moves and game
+// indices that make up most of a Pokemon; GetPokemon fetches those on
+// demand.
+type PokemonSummary struct {
+	ID             int              `json:"id"`
+	Name           string           `json:"name"`
+	BaseExperience int              `json:"base_experience"`
+	Height         int              `json:"height"`
+	Weight         int              `json:"weight"`
+	Species        NamedAPIResource `json:"species"`
+	Stats          []PokemonStat    `json:"stats"`
+	Types          []PokemonType    `json:"types"`
+}
+
+// Summary returns the summary of an already fetched Pokemon.
+func (p Pokemon) Summary() PokemonSummary {
+	return PokemonSummary{
+		ID:             p.ID,
+		Name:           p.Name,
+		BaseExperience: p.BaseExperience,
+		Height:         p.Height,
+		Weight:         p.Weight,
+		Species:        p.Species,
+		Stats:          p.Stats,
+		Types:          p.Types,
+	}
+}
+
+func (p PokemonSummary) PrintDetails() {
 	fmt.Printf("Name: %s\n", p.Name)
 	fmt.Printf("Height: %d\n", p.Height)
 	fmt.Printf("Weight: %d\n", p.Weight)
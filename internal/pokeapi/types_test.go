@@ -0,0 +1,94 @@
+package pokeapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypeDamageFrom(t *testing.T) {
+	body := []byte(`{
+		"id": 12,
+		"name": "grass",
+		"damage_relations": {
+			"double_damage_from": [{"name": "fire", "url": "https://pokeapi.co/api/v2/type/10/"}],
+			"half_damage_from": [{"name": "water", "url": "https://pokeapi.co/api/v2/type/11/"}],
+			"no_damage_from": []
+		},
+		"names": [{"name": "Pflanze", "language": {"name": "de", "url": "https://pokeapi.co/api/v2/language/6/"}}]
+	}`)
+	grass, err := Decode[Type]("https://pokeapi.co/api/v2/type/grass", body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if grass.Names[0].Language.Name != "de" {
+		t.Errorf("expected names to be decoded, got %+v", grass.Names)
+	}
+	for attack, want := range map[string]float64{"fire": 2, "water": 0.5, "normal": 1} {
+		if got := grass.DamageRelations.DamageFrom(attack); got != want {
+			t.Errorf("DamageFrom(%s) = %v, expected %v", attack, got, want)
+		}
+	}
+}
+
+func TestDecodeEvolutionChain(t *testing.T) {
+	body := []byte(`{
+		"id": 10,
+		"baby_trigger_item": null,
+		"chain": {
+			"is_baby": false,
+			"species": {"name": "pichu", "url": "https://pokeapi.co/api/v2/pokemon-species/172/"},
+			"evolution_details": [],
+			"evolves_to": [{
+				"species": {"name": "pikachu", "url": "https://pokeapi.co/api/v2/pokemon-species/25/"},
+				"evolution_details": [{"min_happiness": 220, "trigger": {"name": "level-up", "url": "https://pokeapi.co/api/v2/evolution-trigger/1/"}, "gender": null}],
+				"evolves_to": [{
+					"species": {"name": "raichu", "url": "https://pokeapi.co/api/v2/pokemon-species/26/"},
+					"evolution_details": [{"item": {"name": "thunder-stone", "url": "https://pokeapi.co/api/v2/item/83/"}}],
+					"evolves_to": []
+				}]
+			}]
+		}
+	}`)
+	chain, err := Decode[EvolutionChain]("https://pokeapi.co/api/v2/evolution-chain/10/", body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pikachu := chain.Chain.EvolvesTo[0]
+	if pikachu.Species.Name != "pikachu" || pikachu.EvolutionDetails[0].MinHappiness != 220 {
+		t.Errorf("unexpected link %+v", pikachu)
+	}
+	raichu := pikachu.EvolvesTo[0]
+	if raichu.EvolutionDetails[0].Item.Name != "thunder-stone" {
+		t.Errorf("unexpected link %+v", raichu)
+	}
+}
+
+func TestPokemonSummary(t *testing.T) {
+	body := []byte(`{
+		"id": 25,
+		"name": "pikachu",
+		"base_experience": 112,
+		"height": 4,
+		"weight": 60,
+		"species": {"name": "pikachu", "url": "https://pokeapi.co/api/v2/pokemon-species/25/"},
+		"stats": [{"base_stat": 90, "effort": 2, "stat": {"name": "speed", "url": "https://pokeapi.co/api/v2/stat/6/"}}],
+		"types": [{"slot": 1, "type": {"name": "electric", "url": "https://pokeapi.co/api/v2/type/13/"}}],
+		"moves": [{"move": {"name": "thunder-shock", "url": "https://pokeapi.co/api/v2/move/84/"}, "version_group_details": []}],
+		"sprites": {"front_default": "https://example.com/25.png"}
+	}`)
+	url := "https://pokeapi.co/api/v2/pokemon/pikachu"
+	summary, err := Decode[PokemonSummary](url, body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	full, err := Decode[Pokemon](url, body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(summary, full.Summary()) {
+		t.Errorf("decoded summary %+v differs from the summary of the full Pokemon %+v", summary, full.Summary())
+	}
+	if summary.Stats[0].BaseStat != 90 || summary.Types[0].Type.Name != "electric" || summary.Species.Name != "pikachu" {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+}
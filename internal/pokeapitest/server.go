@@ -0,0 +1,209 @@
+// Package pokeapitest provides a fake PokeAPI for tests and offline
+// development. It serves a small set of fixtures recorded from
+// pokeapi.co and can be told to fail requests on demand.
+package pokeapitest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tobiaspartzsch/pokedex/internal/pokeapi"
+)
+
+// recordedBaseURL is the base URL links in the fixtures were recorded with.
+// The server rewrites it to its own, so links can be followed.
+const recordedBaseURL = "https://pokeapi.co/api/v2/"
+
+// Fixtures are laid out as <endpoint>.json for the full list of an
+// endpoint and <endpoint>/<name>.json for a resource.
+//
+//go:embed fixtures
+var fixtures embed.FS
+
+// Server is a fake PokeAPI below /api/v2 of an httptest.Server. Resources
+// can be requested by name or id, lists are paginated with offset and
+// limit like on pokeapi.co.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	resources map[string][]byte                       // by "endpoint/name" and "endpoint/id"
+	lists     map[string]pokeapi.NamedAPIResourceList // by endpoint
+	failures  map[string][]int                        // statuses the next requests of a path fail with
+	requests  map[string]int                          // by path
+}
+
+// NewServer starts a server with the recorded fixtures. Stop it with
+// Close.
+func NewServer() *Server {
+	s := &Server{
+		resources: map[string][]byte{},
+		lists:     map[string]pokeapi.NamedAPIResourceList{},
+		failures:  map[string][]int{},
+		requests:  map[string]int{},
+	}
+	if err := s.loadFixtures(); err != nil {
+		panic("pokeapitest: loading fixtures: " + err.Error())
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// BaseURL is the URL to use as pokeapi.Client.BaseURL.
+func (s *Server) BaseURL() string {
+	return s.URL + "/api/v2"
+}
+
+// NewClient returns a client for the server without rate limiting and
+// with quick retries.
+func (s *Server) NewClient() *pokeapi.Client {
+	c := pokeapi.NewClient(s.BaseURL(), time.Second)
+	c.Limiter = nil
+	c.Retry = pokeapi.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+	}
+	return c
+}
+
+// SetResource adds a resource or replaces a fixture, e.g. to change a
+// capture rate. Links in body may use the recorded pokeapi.co base URL.
+func (s *Server) SetResource(endpoint, name string, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources[endpoint+"/"+name] = []byte(body)
+	var ids struct {
+		ID int `json:"id"`
+	}
+	if json.Unmarshal([]byte(body), &ids) == nil && ids.ID != 0 {
+		s.resources[endpoint+"/"+strconv.Itoa(ids.ID)] = []byte(body)
+	}
+}
+
+// FailNext makes the next times requests of path, e.g. "pokemon/pikachu"
+// or "location-area", fail with status. A 429 or 503 comes with a
+// Retry-After of 0 so clients retry right away.
+func (s *Server) FailNext(path string, status int, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for range times {
+		s.failures[path] = append(s.failures[path], status)
+	}
+}
+
+// Requests returns how often path has been requested, failed requests
+// included.
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	rest, ok := strings.CutPrefix(r.URL.Path, "/api/v2/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	p := strings.Trim(rest, "/")
+
+	s.mu.Lock()
+	s.requests[p]++
+	status := 0
+	if queued := s.failures[p]; len(queued) > 0 {
+		status, s.failures[p] = queued[0], queued[1:]
+	}
+	s.mu.Unlock()
+	if status != 0 {
+		if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+			w.Header().Set("Retry-After", "0")
+		}
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	segments := strings.Split(p, "/")
+	var body []byte
+	switch len(segments) {
+	case 1:
+		body, ok = s.listPage(segments[0], r.URL.Query())
+	case 2:
+		s.mu.Lock()
+		body, ok = s.resources[p]
+		s.mu.Unlock()
+	}
+	if !ok {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write([]byte(strings.ReplaceAll(string(body), recordedBaseURL, s.BaseURL()+"/")))
+}
+
+// listPage slices the list of endpoint the way pokeapi.co does.
+func (s *Server) listPage(endpoint string, query url.Values) ([]byte, bool) {
+	s.mu.Lock()
+	list, ok := s.lists[endpoint]
+	s.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = pokeapi.DefaultPageSize
+	}
+	start := min(offset, len(list.Results))
+	end := min(offset+limit, len(list.Results))
+
+	page := pokeapi.NamedAPIResourceList{Count: len(list.Results), Results: list.Results[start:end]}
+	pageURL := func(offset int) string {
+		return fmt.Sprintf("%s%s?offset=%d&limit=%d", recordedBaseURL, endpoint, offset, limit)
+	}
+	if end < len(list.Results) {
+		page.Next = pageURL(end)
+	}
+	if start > 0 {
+		page.Previous = pageURL(max(start-limit, 0))
+	}
+	body, err := json.Marshal(page)
+	return body, err == nil
+}
+
+func (s *Server) loadFixtures() error {
+	return fs.WalkDir(fixtures, "fixtures", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		body, err := fixtures.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel := strings.TrimSuffix(strings.TrimPrefix(p, "fixtures/"), ".json")
+		endpoint, name, isResource := strings.Cut(rel, "/")
+		if !isResource {
+			var list pokeapi.NamedAPIResourceList
+			if err := json.Unmarshal(body, &list); err != nil {
+				return fmt.Errorf("%s: %w", p, err)
+			}
+			s.lists[endpoint] = list
+			return nil
+		}
+		s.SetResource(endpoint, name, string(body))
+		return nil
+	})
+}
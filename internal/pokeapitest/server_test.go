@@ -0,0 +1,70 @@
+package pokeapitest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/tobiaspartzsch/pokedex/internal/pokeapi"
+)
+
+func TestServerResources(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.NewClient()
+	ctx := context.Background()
+
+	pokemon, err := c.GetPokemon(ctx, c.URL("pokemon", "pikachu"))
+	if err != nil || pokemon.ID != 25 {
+		t.Fatalf("unexpected Pokemon %+v, %v", pokemon, err)
+	}
+	// Links point back at the server
+	species, err := pokeapi.Resolve[pokeapi.PokemonSpecies](ctx, c, pokemon.Species)
+	if err != nil || species.CaptureRate != 190 {
+		t.Fatalf("unexpected species %+v, %v", species, err)
+	}
+
+	_, err = c.GetPokemon(ctx, c.URL("pokemon", "missingno"))
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestServerPaginates(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	var pages, areas int
+	for page, err := range c.Pages(context.Background(), "location-area", 0, 10) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pages++
+		areas += len(page.Results)
+	}
+	if pages != 3 || areas != 25 {
+		t.Errorf("expected 25 areas on 3 pages, got %d on %d", areas, pages)
+	}
+}
+
+func TestServerFailNext(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	s.FailNext("pokemon/pikachu", http.StatusTooManyRequests, 2)
+	if _, err := c.GetPokemon(context.Background(), c.URL("pokemon", "pikachu")); err != nil {
+		t.Fatalf("expected the client to retry past two 429s, got %v", err)
+	}
+	if got := s.Requests("pokemon/pikachu"); got != 3 {
+		t.Errorf("expected 3 requests, got %d", got)
+	}
+
+	s.FailNext("pokemon/magikarp", http.StatusTooManyRequests, 3)
+	_, err := c.GetPokemon(context.Background(), c.URL("pokemon", "magikarp"))
+	if !errors.Is(err, pokeapi.ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited once retries are used up, got %v", err)
+	}
+}
@@ -0,0 +1,293 @@
+package pokecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	diskEntrySuffix = ".json"
+	diskTempPrefix  = ".tmp-"
+	// staleTempAge is how old a temporary file has to be before Prune
+	// assumes the process that was writing it is gone.
+	staleTempAge = time.Hour
+)
+
+// DiskStore keeps entries as one file per key in a directory, so they
+// survive restarts. Files are replaced atomically, which makes it safe for
+// several processes to share a directory.
+type DiskStore struct {
+	// KeepExpired serves entries past their stale period as stale instead
+	// of removing them, for when there is no way to refetch them.
+	KeepExpired bool
+
+	dir         string
+	ttl         time.Duration
+	staleTTL    time.Duration
+	hits        atomic.Int64
+	misses      atomic.Int64
+	expirations atomic.Int64
+}
+
+type diskEntry struct {
+	Key          string    `json:"key"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`           // end of freshness
+	StaleUntil   time.Time `json:"stale_until,omitzero"` // end of the stale period, zero if there is none
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Val          []byte    `json:"val"`
+}
+
+// DefaultDiskDir returns the pokedex directory below the user's cache
+// directory, e.g. $XDG_CACHE_HOME/pokedex.
+func DefaultDiskDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pokedex"), nil
+}
+
+// NewDiskStore stores entries in dir, creating it if needed. Entries are
+// fresh for ttl after they were added, 0 means forever, and are kept for
+// another staleTTL to be served stale.
+func NewDiskStore(dir string, ttl, staleTTL time.Duration) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("couldn't create cache directory: %w", err)
+	}
+	return &DiskStore{dir: dir, ttl: ttl, staleTTL: staleTTL}, nil
+}
+
+func (d *DiskStore) Dir() string {
+	return d.dir
+}
+
+func (d *DiskStore) Add(key string, val []byte) bool {
+	return d.Set(key, Entry{Val: val})
+}
+
+// Set stores entry under key, a zero CreatedAt means now.
+func (d *DiskStore) Set(key string, entry Entry) bool {
+	stored := diskEntry{
+		Key:          key,
+		CreatedAt:    entry.CreatedAt,
+		ETag:         entry.ETag,
+		LastModified: entry.LastModified,
+		Val:          entry.Val,
+	}
+	if stored.CreatedAt.IsZero() {
+		stored.CreatedAt = time.Now()
+	}
+	if d.ttl > 0 {
+		stored.ExpiresAt = stored.CreatedAt.Add(d.ttl)
+		if d.staleTTL > 0 {
+			stored.StaleUntil = stored.ExpiresAt.Add(d.staleTTL)
+		}
+	}
+	return d.write(stored) == nil
+}
+
+func (d *DiskStore) Get(key string) ([]byte, bool) {
+	entry, ok := d.Lookup(key)
+	if !ok || entry.Stale {
+		return nil, false
+	}
+	return entry.Val, true
+}
+
+// Lookup returns the entry under key. Expired entries are only returned
+// with KeepExpired, their files are removed by Prune.
+func (d *DiskStore) Lookup(key string) (Entry, bool) {
+	entry, ok := d.Peek(key)
+	if ok {
+		d.hits.Add(1)
+	} else {
+		d.misses.Add(1)
+	}
+	return entry, ok
+}
+
+func (d *DiskStore) Peek(key string) (Entry, bool) {
+	stored, err := readDiskEntry(d.path(key))
+	if err != nil || stored.Key != key {
+		return Entry{}, false
+	}
+	now := time.Now()
+	// The file of an expired entry is left to Prune: another process may
+	// have renamed a fresh entry into place since it was read
+	if stored.expired(now) && !d.KeepExpired {
+		return Entry{}, false
+	}
+	return Entry{
+		Val:          stored.Val,
+		ETag:         stored.ETag,
+		LastModified: stored.LastModified,
+		CreatedAt:    stored.CreatedAt,
+		Stale:        stored.stale(now),
+	}, true
+}
+
+func (d *DiskStore) Delete(key string) {
+	os.Remove(d.path(key))
+}
+
+// Close is a no-op, a DiskStore holds no open files between calls.
+func (d *DiskStore) Close() error {
+	return nil
+}
+
+// List reads every entry file, so it is as slow as the directory is big.
+func (d *DiskStore) List(prefix string) []EntryInfo {
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil
+	}
+	now := time.Now()
+	var infos []EntryInfo
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), diskEntrySuffix) {
+			continue
+		}
+		stored, err := readDiskEntry(filepath.Join(d.dir, file.Name()))
+		if err != nil || (stored.expired(now) && !d.KeepExpired) || !strings.HasPrefix(stored.Key, prefix) {
+			continue
+		}
+		infos = append(infos, EntryInfo{
+			Key:       stored.Key,
+			Size:      int64(len(stored.Key) + len(stored.Val)),
+			CreatedAt: stored.CreatedAt,
+			Stale:     stored.stale(now),
+		})
+	}
+	return infos
+}
+
+// Stats counts the entry files in the directory, including expired ones
+// that have not been pruned yet. Bytes is the size of the files.
+func (d *DiskStore) Stats() Stats {
+	stats := Stats{
+		Hits:        d.hits.Load(),
+		Misses:      d.misses.Load(),
+		Expirations: d.expirations.Load(),
+	}
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return stats
+	}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), diskEntrySuffix) {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+	}
+	return stats
+}
+
+// Prune removes expired (past their stale period) and unreadable entries as well as temporary files
+// left behind by crashed processes, and returns how many files it removed.
+func (d *DiskStore) Prune() (int, error) {
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't list cache directory: %w", err)
+	}
+
+	now := time.Now()
+	removed := 0
+	for _, file := range files {
+		name := file.Name()
+		path := filepath.Join(d.dir, name)
+		switch {
+		case strings.HasPrefix(name, diskTempPrefix):
+			info, err := file.Info()
+			if err != nil || now.Sub(info.ModTime()) < staleTempAge {
+				continue
+			}
+		case strings.HasSuffix(name, diskEntrySuffix):
+			entry, err := readDiskEntry(path)
+			if err == nil && !entry.expired(now) {
+				continue
+			}
+		default:
+			continue
+		}
+		if err := os.Remove(path); err == nil {
+			removed++
+		}
+	}
+	d.expirations.Add(int64(removed))
+	return removed, nil
+}
+
+func (d *DiskStore) write(entry diskEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(d.dir, diskTempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	// Readers in other processes see either the old or the new file,
+	// never a partially written one.
+	if err := os.Rename(tmp.Name(), d.path(entry.Key)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func (d *DiskStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+diskEntrySuffix)
+}
+
+func readDiskEntry(path string) (diskEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return diskEntry{}, err
+	}
+	var entry diskEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return diskEntry{}, err
+	}
+	if entry.Key == "" {
+		return diskEntry{}, errors.New("cache file without key")
+	}
+	return entry, nil
+}
+
+// stale reports whether e is past its freshness.
+func (e diskEntry) stale(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && now.After(e.ExpiresAt)
+}
+
+// expired reports whether e is past its stale period and has to go.
+func (e diskEntry) expired(now time.Time) bool {
+	if e.StaleUntil.IsZero() {
+		return e.stale(now)
+	}
+	return now.After(e.StaleUntil)
+}
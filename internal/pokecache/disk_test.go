@@ -0,0 +1,108 @@
+package pokecache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDiskStoreSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	first, err := NewDiskStore(dir, time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Add("https://example.com", []byte("testdata")) {
+		t.Fatalf("expected add to succeed")
+	}
+
+	second, err := NewDiskStore(dir, time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	val, ok := second.Get("https://example.com")
+	if !ok || string(val) != "testdata" {
+		t.Errorf("expected to find value written by another store, got %q, %v", val, ok)
+	}
+	if _, ok := second.Get("https://example.com/other"); ok {
+		t.Errorf("expected not to find unknown key")
+	}
+}
+
+func TestDiskStoreExpiryAndPrune(t *testing.T) {
+	dir := t.TempDir()
+	d, err := NewDiskStore(dir, time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-2 * time.Hour)
+	d.write(diskEntry{Key: "expired", CreatedAt: past, ExpiresAt: past.Add(time.Hour), Val: []byte("old")})
+	d.write(diskEntry{Key: "also-expired", CreatedAt: past, ExpiresAt: past.Add(time.Hour), Val: []byte("old")})
+	d.Add("fresh", []byte("new"))
+	os.WriteFile(filepath.Join(dir, "garbage"+diskEntrySuffix), []byte("{not json"), 0o644)
+
+	if _, ok := d.Get("expired"); ok {
+		t.Errorf("expected expired entry to be missing")
+	}
+	if _, err := os.Stat(d.path("expired")); err != nil {
+		t.Errorf("expected Lookup to leave the expired file to Prune, got %v", err)
+	}
+
+	removed, err := d.Prune()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 3 {
+		t.Errorf("expected prune to remove both expired files and the garbage file, removed %d", removed)
+	}
+	if _, ok := d.Get("fresh"); !ok {
+		t.Errorf("expected fresh entry to survive pruning")
+	}
+}
+
+func TestDiskStoreStaleEntries(t *testing.T) {
+	d, err := NewDiskStore(t.TempDir(), time.Hour, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Set("stale", Entry{
+		Val:          []byte("old"),
+		ETag:         `"v1"`,
+		LastModified: "Wed, 01 Jan 2025 12:00:00 GMT",
+		CreatedAt:    time.Now().Add(-90 * time.Minute),
+	})
+	d.Set("expired", Entry{Val: []byte("older"), CreatedAt: time.Now().Add(-3 * time.Hour)})
+
+	if _, ok := d.Get("stale"); ok {
+		t.Errorf("expected Get to not return a stale entry")
+	}
+	entry, ok := d.Lookup("stale")
+	if !ok || !entry.Stale || entry.ETag != `"v1"` || entry.LastModified == "" {
+		t.Errorf("expected Lookup to return the stale entry with its validators, got %+v, %v", entry, ok)
+	}
+	if _, ok := d.Lookup("expired"); ok {
+		t.Errorf("expected an entry past its stale period to be gone")
+	}
+}
+
+func TestDiskStoreKeepExpired(t *testing.T) {
+	d, err := NewDiskStore(t.TempDir(), time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.KeepExpired = true
+	past := time.Now().Add(-2 * time.Hour)
+	d.write(diskEntry{Key: "expired", CreatedAt: past, ExpiresAt: past.Add(time.Hour), Val: []byte("old")})
+
+	entry, ok := d.Lookup("expired")
+	if !ok || !entry.Stale || string(entry.Val) != "old" {
+		t.Fatalf("expected expired entry to be served stale, got %+v, %v", entry, ok)
+	}
+	if _, ok := d.Get("expired"); ok {
+		t.Errorf("expected Get to skip the expired entry")
+	}
+	if infos := d.List(""); len(infos) != 1 {
+		t.Errorf("expected expired entry to be listed, got %v", infos)
+	}
+}
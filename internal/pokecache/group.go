@@ -0,0 +1,76 @@
+package pokecache
+
+import (
+	"context"
+	"sync"
+)
+
+// Group coalesces concurrent loads of the same key, so that a burst of
+// misses for one URL results in a single fetch. The zero value is ready
+// to use.
+type Group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	val     []byte
+	err     error
+}
+
+// Do calls fn once for all concurrent callers with the same key and hands
+// every one of them its result. fn runs with a context that is only
+// cancelled once all callers have given up, so one impatient caller does
+// not fail the others.
+func (g *Group) Do(ctx context.Context, key string, fn func(context.Context) ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*call{}
+	}
+	c, inFlight := g.calls[key]
+	if !inFlight {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &call{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = c
+		go g.run(callCtx, key, c, fn)
+	}
+	c.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		g.leave(key, c)
+		return nil, ctx.Err()
+	}
+}
+
+func (g *Group) run(ctx context.Context, key string, c *call, fn func(context.Context) ([]byte, error)) {
+	c.val, c.err = fn(ctx)
+	g.mu.Lock()
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+	c.cancel()
+	close(c.done)
+}
+
+// leave gives up on c and cancels it if nobody else is waiting.
+func (g *Group) leave(key string, c *call) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	c.waiters--
+	if c.waiters > 0 {
+		return
+	}
+	// Later callers must not join a call that is being cancelled.
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+	c.cancel()
+}
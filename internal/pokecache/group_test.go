@@ -0,0 +1,122 @@
+package pokecache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGroupCoalesces(t *testing.T) {
+	var g Group
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) ([]byte, error) {
+		calls.Add(1)
+		<-release
+		return []byte("testdata"), nil
+	}
+
+	const callers = 10
+	var started, finished sync.WaitGroup
+	results := make([][]byte, callers)
+	for i := range callers {
+		started.Add(1)
+		finished.Add(1)
+		go func() {
+			defer finished.Done()
+			started.Done()
+			results[i], _ = g.Do(context.Background(), "https://example.com", fn)
+		}()
+	}
+	started.Wait()
+	// Give every caller the chance to join before the fetch completes.
+	for {
+		g.mu.Lock()
+		c := g.calls["https://example.com"]
+		joined := c != nil && c.waiters == callers
+		g.mu.Unlock()
+		if joined {
+			break
+		}
+	}
+	close(release)
+	finished.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 call, got %d", calls.Load())
+	}
+	for i, val := range results {
+		if string(val) != "testdata" {
+			t.Errorf("caller %d got %q", i, val)
+		}
+	}
+}
+
+func TestGroupCancellation(t *testing.T) {
+	var g Group
+	fnCtx := make(chan context.Context, 1)
+	release := make(chan struct{})
+	fn := func(ctx context.Context) ([]byte, error) {
+		fnCtx <- ctx
+		<-release
+		return []byte("testdata"), ctx.Err()
+	}
+
+	impatient, cancel := context.WithCancel(context.Background())
+	impatientErr := make(chan error, 1)
+	go func() {
+		_, err := g.Do(impatient, "key", fn)
+		impatientErr <- err
+	}()
+	shared := <-fnCtx
+
+	patientResult := make(chan []byte, 1)
+	go func() {
+		val, _ := g.Do(context.Background(), "key", fn)
+		patientResult <- val
+	}()
+	for {
+		g.mu.Lock()
+		joined := g.calls["key"].waiters == 2
+		g.mu.Unlock()
+		if joined {
+			break
+		}
+	}
+
+	cancel()
+	if err := <-impatientErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the impatient caller to be cancelled, got %v", err)
+	}
+	if shared.Err() != nil {
+		t.Errorf("expected the shared call to go on while someone is waiting")
+	}
+
+	close(release)
+	if val := <-patientResult; string(val) != "testdata" {
+		t.Errorf("expected the patient caller to get the result, got %q", val)
+	}
+}
+
+func TestGroupCancelsWhenEveryoneLeaves(t *testing.T) {
+	var g Group
+	fnCtx := make(chan context.Context, 1)
+	fn := func(ctx context.Context) ([]byte, error) {
+		fnCtx <- ctx
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		g.Do(ctx, "key", fn)
+		close(done)
+	}()
+	shared := <-fnCtx
+	cancel()
+	<-done
+	<-shared.Done()
+}
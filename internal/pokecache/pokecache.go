@@ -1,62 +1,264 @@
 package pokecache
 
 import (
+	"container/list"
+	"strings"
 	"sync"
 	"time"
 )
 
+// Config controls how long entries live and how many are kept. Once one of
+// the bounds is exceeded, the least recently used entries are evicted.
+type Config struct {
+	TTL          time.Duration    // how long an entry is fresh
+	StaleTTL     time.Duration    // how long an entry is kept after the TTL to be served stale
+	ReapInterval time.Duration    // how often expired entries are removed, defaults to TTL
+	MaxEntries   int              // upper bound on the number of entries, 0 means unbounded
+	MaxBytes     int64            // upper bound on the size of keys and values, 0 means unbounded
+	Now          func() time.Time // clock used for expiry, defaults to time.Now
+}
+
 type Cache struct {
-	entries  map[string]cacheEntry
-	mu       sync.Mutex
-	interval time.Duration
+	entries   map[string]*list.Element
+	lru       *list.List // of *cacheEntry, most recently used first
+	bytes     int64
+	mu        sync.Mutex
+	config    Config
+	stats     Stats         // counters only, Entries and Bytes are filled in by Stats
+	stop      chan struct{} // closed by Close to end the reaper
+	reaped    chan struct{} // closed once the reaper has ended
+	closeOnce sync.Once
 }
 
 type cacheEntry struct {
-	createdAt time.Time
-	val       []byte
+	key string
+	Entry
 }
 
+func (e *cacheEntry) size() int64 {
+	return int64(len(e.key) + len(e.Val) + len(e.ETag) + len(e.LastModified))
+}
+
+// Add stores val under key. It returns false if the entry alone is larger
+// than MaxBytes and therefore can't be cached.
 func (c *Cache) Add(key string, val []byte) bool {
+	return c.Set(key, Entry{Val: val})
+}
+
+// Set stores entry under key, a zero CreatedAt means now.
+func (c *Cache) Set(key string, entry Entry) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	newEntry := cacheEntry{
-		createdAt: time.Now(),
-		val:       val,
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = c.config.Now()
 	}
-	c.entries[key] = newEntry
-	return true
+	entry.Stale = false
+	return c.insert(key, entry)
 }
 
+// Get only returns fresh entries, use Lookup to also see stale ones.
 func (c *Cache) Get(key string) ([]byte, bool) {
+	entry, ok := c.Lookup(key)
+	if !ok || entry.Stale {
+		return nil, false
+	}
+	return entry.Val, true
+}
+
+func (c *Cache) Lookup(key string) (Entry, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	entry, exists := c.entries[key]
+	elem, exists := c.entries[key]
 	if !exists {
-		return nil, false
+		c.stats.Misses++
+		return Entry{}, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	now := c.config.Now()
+	if c.expired(entry, now) {
+		c.remove(elem)
+		c.stats.Expirations++
+		c.stats.Misses++
+		return Entry{}, false
+	}
+	c.stats.Hits++
+	c.lru.MoveToFront(elem)
+	result := entry.Entry
+	result.Stale = c.stale(entry, now)
+	return result, true
+}
+
+// Peek leaves expired entries to Lookup and the reaper.
+func (c *Cache) Peek(key string) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, exists := c.entries[key]
+	if !exists {
+		return Entry{}, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	now := c.config.Now()
+	if c.expired(entry, now) {
+		return Entry{}, false
+	}
+	result := entry.Entry
+	result.Stale = c.stale(entry, now)
+	return result, true
+}
+
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, exists := c.entries[key]; exists {
+		c.remove(elem)
+	}
+}
+
+func (c *Cache) List(prefix string) []EntryInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.config.Now()
+	var infos []EntryInfo
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*cacheEntry)
+		if !strings.HasPrefix(entry.key, prefix) || c.expired(entry, now) {
+			continue
+		}
+		infos = append(infos, EntryInfo{
+			Key:       entry.key,
+			Size:      entry.size(),
+			CreatedAt: entry.CreatedAt,
+			Stale:     c.stale(entry, now),
+		})
+	}
+	return infos
+}
+
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	stats.Bytes = c.bytes
+	return stats
+}
+
+// insert adds an entry. The caller must hold c.mu.
+func (c *Cache) insert(key string, entry Entry) bool {
+	newEntry := &cacheEntry{key: key, Entry: entry}
+	if c.config.MaxBytes > 0 && newEntry.size() > c.config.MaxBytes {
+		return false
+	}
+
+	if elem, exists := c.entries[key]; exists {
+		c.remove(elem)
+	}
+	c.entries[key] = c.lru.PushFront(newEntry)
+	c.bytes += newEntry.size()
+
+	for c.overBudget() {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+	return true
+}
+
+func (c *Cache) Config() Config {
+	return c.config
+}
+
+// overBudget reports whether an entry has to go. The caller must hold c.mu.
+func (c *Cache) overBudget() bool {
+	if c.config.MaxEntries > 0 && len(c.entries) > c.config.MaxEntries {
+		return true
+	}
+	return c.config.MaxBytes > 0 && c.bytes > c.config.MaxBytes
+}
+
+// remove drops an entry. The caller must hold c.mu.
+func (c *Cache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size()
+}
+
+// Close stops the reaper. The cache stays usable, but expired entries are
+// only dropped when they are read. Close is safe to call more than once.
+func (c *Cache) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		<-c.reaped
+	})
+	return nil
+}
+
+// stale reports whether entry is older than the TTL, 0 means entries are
+// fresh forever.
+func (c *Cache) stale(entry *cacheEntry, now time.Time) bool {
+	return c.config.TTL > 0 && entry.CreatedAt.Add(c.config.TTL).Before(now)
+}
+
+// expired reports whether entry is past the TTL and the stale period and
+// has to go.
+func (c *Cache) expired(entry *cacheEntry, now time.Time) bool {
+	return c.config.TTL > 0 && entry.CreatedAt.Add(c.config.TTL+c.config.StaleTTL).Before(now)
+}
+
+// reap removes all expired entries.
+func (c *Cache) reap() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.config.Now()
+	for _, elem := range c.entries {
+		if c.expired(elem.Value.(*cacheEntry), now) {
+			c.remove(elem)
+			c.stats.Expirations++
+		}
 	}
-	return entry.val, true
 }
 
 func (c *Cache) reapLoop() {
-	ticker := time.NewTicker(c.interval)
+	defer close(c.reaped)
+	ticker := time.NewTicker(c.config.ReapInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		c.mu.Lock()
-		for key, entry := range c.entries {
-			if entry.createdAt.Add(c.interval).Before(time.Now()) {
-				delete(c.entries, key)
-			}
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.reap()
 		}
-		c.mu.Unlock()
 	}
 }
 
+// NewCache creates a cache whose entries expire after interval, checked
+// every interval.
 func NewCache(interval time.Duration) *Cache {
+	return New(Config{
+		TTL:          interval,
+		ReapInterval: interval,
+	})
+}
+
+func New(config Config) *Cache {
+	if config.ReapInterval <= 0 {
+		config.ReapInterval = config.TTL
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
 	c := &Cache{
-		entries:  map[string]cacheEntry{},
-		interval: interval,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+		config:  config,
+		stop:    make(chan struct{}),
+		reaped:  make(chan struct{}),
+	}
+	if config.ReapInterval > 0 {
+		go c.reapLoop()
+	} else {
+		close(c.reaped)
 	}
-	go c.reapLoop()
 	return c
 }
@@ -2,6 +2,7 @@ package pokecache
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 )
@@ -25,6 +26,7 @@ func TestAddGet(t *testing.T) {
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
 			cache := NewCache(interval)
+			defer cache.Close()
 			cache.Add(c.key, c.val)
 			val, ok := cache.Get(c.key)
 			if !ok {
@@ -39,10 +41,33 @@ func TestAddGet(t *testing.T) {
 	}
 }
 
+// fakeClock is a manually advanced clock for expiry tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
 func TestReapLoop(t *testing.T) {
 	const baseTime = 5 * time.Millisecond
-	const waitTime = baseTime + 5*time.Millisecond
-	cache := NewCache(baseTime)
+	clock := newFakeClock()
+	cache := New(Config{TTL: baseTime, ReapInterval: time.Hour, Now: clock.Now})
+	defer cache.Close()
 	cache.Add("https://example.com", []byte("testdata"))
 
 	_, ok := cache.Get("https://example.com")
@@ -51,11 +76,169 @@ func TestReapLoop(t *testing.T) {
 		return
 	}
 
-	time.Sleep(waitTime)
+	clock.Advance(baseTime + time.Millisecond)
+	cache.reap()
 
-	_, ok = cache.Get("https://example.com")
-	if ok {
-		t.Errorf("expected to not find key")
-		return
+	if entries := cache.Stats().Entries; entries != 0 {
+		t.Errorf("expected reaper to remove the entry, %d left", entries)
+	}
+}
+
+func TestGetIgnoresExpiredEntries(t *testing.T) {
+	clock := newFakeClock()
+	cache := New(Config{TTL: time.Minute, ReapInterval: time.Hour, Now: clock.Now})
+	defer cache.Close()
+	cache.Add("https://example.com", []byte("testdata"))
+
+	clock.Advance(59 * time.Second)
+	if _, ok := cache.Get("https://example.com"); !ok {
+		t.Errorf("expected to find key before the TTL passed")
+	}
+
+	clock.Advance(2 * time.Second)
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected to not find key after the TTL passed")
+	}
+}
+
+func TestClose(t *testing.T) {
+	cache := NewCache(time.Millisecond)
+	if err := cache.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-cache.reaped:
+	default:
+		t.Errorf("expected the reaper to be stopped")
+	}
+	if err := cache.Close(); err != nil {
+		t.Errorf("expected closing twice to be fine, got %v", err)
+	}
+
+	cache.Add("https://example.com", []byte("testdata"))
+	if _, ok := cache.Get("https://example.com"); !ok {
+		t.Errorf("expected a closed cache to stay usable")
+	}
+}
+
+func TestMaxEntries(t *testing.T) {
+	cache := New(Config{TTL: time.Minute, MaxEntries: 2})
+	defer cache.Close()
+	cache.Add("first", []byte("1"))
+	cache.Add("second", []byte("2"))
+	cache.Add("second", []byte("2 again"))
+	if cache.Stats().Entries != 2 {
+		t.Errorf("expected overwriting a key to keep 2 entries, got %d", cache.Stats().Entries)
+	}
+
+	cache.Add("third", []byte("3"))
+	if cache.Stats().Entries != 2 {
+		t.Errorf("expected 2 entries, got %d", cache.Stats().Entries)
+	}
+	if _, ok := cache.Get("first"); ok {
+		t.Errorf("expected oldest entry to be evicted")
+	}
+	if _, ok := cache.Get("third"); !ok {
+		t.Errorf("expected newest entry to be kept")
+	}
+}
+
+func TestLRUEviction(t *testing.T) {
+	cache := New(Config{TTL: time.Minute, MaxEntries: 2})
+	defer cache.Close()
+	cache.Add("first", []byte("1"))
+	cache.Add("second", []byte("2"))
+	cache.Get("first")
+	cache.Add("third", []byte("3"))
+
+	if _, ok := cache.Get("second"); ok {
+		t.Errorf("expected least recently used entry to be evicted")
+	}
+	if _, ok := cache.Get("first"); !ok {
+		t.Errorf("expected recently read entry to be kept")
+	}
+}
+
+func TestMaxBytes(t *testing.T) {
+	cache := New(Config{TTL: time.Minute, MaxBytes: 20})
+	defer cache.Close()
+	cache.Add("a", []byte("123456789")) // 10 bytes
+	cache.Add("b", []byte("123456789")) // 20 bytes
+	cache.Add("a", []byte("1234"))      // 15 bytes, overwrite shrinks
+	if cache.Stats().Bytes != 15 {
+		t.Errorf("expected 15 bytes, got %d", cache.Stats().Bytes)
+	}
+
+	cache.Add("c", []byte("123456789")) // 25 bytes, b has to go
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("expected least recently used entry to be evicted")
+	}
+	if cache.Stats().Bytes != 15 || cache.Stats().Entries != 2 {
+		t.Errorf("expected 2 entries with 15 bytes, got %d with %d bytes", cache.Stats().Entries, cache.Stats().Bytes)
+	}
+
+	if cache.Add("huge", make([]byte, 100)) {
+		t.Errorf("expected an entry larger than MaxBytes to be rejected")
+	}
+	if cache.Stats().Entries != 2 {
+		t.Errorf("expected rejected entry to leave the cache alone, got %d entries", cache.Stats().Entries)
+	}
+}
+
+func TestStaleEntries(t *testing.T) {
+	clock := newFakeClock()
+	cache := New(Config{TTL: time.Minute, StaleTTL: time.Hour, ReapInterval: time.Hour, Now: clock.Now})
+	defer cache.Close()
+	cache.Set("https://example.com", Entry{Val: []byte("testdata"), ETag: `"v1"`})
+
+	clock.Advance(2 * time.Minute)
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected Get to not return a stale entry")
+	}
+	entry, ok := cache.Lookup("https://example.com")
+	if !ok || !entry.Stale || entry.ETag != `"v1"` {
+		t.Errorf("expected Lookup to return the stale entry with its validators, got %+v, %v", entry, ok)
+	}
+
+	cache.Set("https://example.com", Entry{Val: entry.Val, ETag: entry.ETag})
+	if _, ok := cache.Get("https://example.com"); !ok {
+		t.Errorf("expected a refreshed entry to be fresh again")
+	}
+
+	clock.Advance(2 * time.Hour)
+	cache.reap()
+	if _, ok := cache.Lookup("https://example.com"); ok {
+		t.Errorf("expected the entry to be gone after the stale period")
+	}
+}
+
+func TestStatsAndList(t *testing.T) {
+	clock := newFakeClock()
+	cache := New(Config{TTL: time.Minute, MaxEntries: 2, ReapInterval: time.Hour, Now: clock.Now})
+	defer cache.Close()
+	cache.Add("https://example.com/pokemon/1", []byte("bulbasaur"))
+	cache.Add("https://example.com/pokemon/2", []byte("ivysaur"))
+	cache.Add("https://example.com/location-area/1", []byte("canalave"))
+
+	cache.Get("https://example.com/location-area/1")
+	cache.Get("https://example.com/pokemon/1")
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Evictions != 1 || stats.Entries != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	infos := cache.List("https://example.com/pokemon/")
+	if len(infos) != 1 || infos[0].Key != "https://example.com/pokemon/2" {
+		t.Errorf("unexpected list %+v", infos)
+	}
+	if cache.Stats().Hits != 1 {
+		t.Errorf("expected List to not count as a hit")
+	}
+
+	clock.Advance(2 * time.Minute)
+	cache.reap()
+	if stats := cache.Stats(); stats.Expirations != 2 || stats.Bytes != 0 {
+		t.Errorf("expected both entries to expire, got %+v", stats)
 	}
 }
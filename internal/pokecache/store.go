@@ -0,0 +1,198 @@
+package pokecache
+
+import (
+	"errors"
+	"time"
+)
+
+// Store is a cache backend. The in-memory *Cache, the persistent
+// *DiskStore, Noop and Layered all implement it.
+type Store interface {
+	// Get returns the value under key if it is fresh.
+	Get(key string) ([]byte, bool)
+	// Add stores val under key and reports whether it was stored.
+	Add(key string, val []byte) bool
+	// Lookup returns the entry under key even if it is stale, that is past
+	// its TTL but still kept to be served while it is revalidated.
+	Lookup(key string) (Entry, bool)
+	// Peek is Lookup without counting as a use: stats, eviction order and
+	// the other tiers of a Layered store are left as they are.
+	Peek(key string) (Entry, bool)
+	// Set is Add with validators and an explicit creation time.
+	Set(key string, entry Entry) bool
+	Delete(key string)
+	// List describes the entries whose key starts with prefix, without
+	// counting as a use of them.
+	List(prefix string) []EntryInfo
+	Stats() Stats
+	// Close releases background resources such as the reaper goroutine.
+	Close() error
+}
+
+// Entry is a cached value together with the HTTP validators it was
+// served with, which allow a cheap conditional refresh once it is stale.
+type Entry struct {
+	Val          []byte
+	ETag         string
+	LastModified string
+	CreatedAt    time.Time
+	Stale        bool // set by Lookup, ignored by Set
+}
+
+// EntryInfo describes an entry without its value.
+type EntryInfo struct {
+	Key       string
+	Size      int64
+	CreatedAt time.Time
+	Stale     bool
+}
+
+// Stats describes the contents of a Store and how well it has served.
+type Stats struct {
+	Entries     int
+	Bytes       int64 // size of keys and values
+	Hits        int64 // lookups that found an entry, fresh or stale
+	Misses      int64
+	Evictions   int64 // entries dropped to stay within the size bounds
+	Expirations int64 // entries dropped because they expired
+}
+
+// Noop is a Store that never stores anything.
+type Noop struct{}
+
+func (Noop) Get(key string) ([]byte, bool)    { return nil, false }
+func (Noop) Add(key string, val []byte) bool  { return false }
+func (Noop) Lookup(key string) (Entry, bool)  { return Entry{}, false }
+func (Noop) Peek(key string) (Entry, bool)    { return Entry{}, false }
+func (Noop) Set(key string, entry Entry) bool { return false }
+func (Noop) Delete(key string)                {}
+func (Noop) List(prefix string) []EntryInfo   { return nil }
+func (Noop) Stats() Stats                     { return Stats{} }
+func (Noop) Close() error                     { return nil }
+
+// Layered chains stores from fastest to slowest, e.g. memory in front of
+// disk. Lookups fall through the tiers and a hit in a slower tier is
+// copied into the faster ones, writes and Deletes go to every tier.
+type Layered struct {
+	tiers []Store
+}
+
+func NewLayered(tiers ...Store) *Layered {
+	return &Layered{tiers: tiers}
+}
+
+// Tiers returns the stores of l, fastest first.
+func (l *Layered) Tiers() []Store {
+	return l.tiers
+}
+
+func (l *Layered) Get(key string) ([]byte, bool) {
+	entry, ok := l.Lookup(key)
+	if !ok || entry.Stale {
+		return nil, false
+	}
+	return entry.Val, true
+}
+
+// Lookup prefers the fastest fresh entry over the fastest stale one.
+func (l *Layered) Lookup(key string) (Entry, bool) {
+	var found Entry
+	foundAt := -1
+	for i, tier := range l.tiers {
+		entry, ok := tier.Lookup(key)
+		if !ok {
+			continue
+		}
+		if foundAt < 0 || (found.Stale && !entry.Stale) {
+			found, foundAt = entry, i
+		}
+		if !entry.Stale {
+			break
+		}
+	}
+	if foundAt < 0 {
+		return Entry{}, false
+	}
+	for _, faster := range l.tiers[:foundAt] {
+		faster.Set(key, found)
+	}
+	return found, true
+}
+
+// Peek prefers the fastest fresh entry over the fastest stale one, like
+// Lookup, but doesn't copy it into the faster tiers.
+func (l *Layered) Peek(key string) (Entry, bool) {
+	var found Entry
+	ok := false
+	for _, tier := range l.tiers {
+		entry, exists := tier.Peek(key)
+		if !exists {
+			continue
+		}
+		if !ok || (found.Stale && !entry.Stale) {
+			found, ok = entry, true
+		}
+		if !entry.Stale {
+			break
+		}
+	}
+	return found, ok
+}
+
+// Add reports whether at least one tier stored val.
+func (l *Layered) Add(key string, val []byte) bool {
+	return l.Set(key, Entry{Val: val})
+}
+
+// Set reports whether at least one tier stored entry.
+func (l *Layered) Set(key string, entry Entry) bool {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	stored := false
+	for _, tier := range l.tiers {
+		if tier.Set(key, entry) {
+			stored = true
+		}
+	}
+	return stored
+}
+
+func (l *Layered) Delete(key string) {
+	for _, tier := range l.tiers {
+		tier.Delete(key)
+	}
+}
+
+// List merges the entries of all tiers, preferring the faster ones.
+func (l *Layered) List(prefix string) []EntryInfo {
+	var infos []EntryInfo
+	seen := map[string]bool{}
+	for _, tier := range l.tiers {
+		for _, info := range tier.List(prefix) {
+			if seen[info.Key] {
+				continue
+			}
+			seen[info.Key] = true
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
+// Close closes every tier.
+func (l *Layered) Close() error {
+	var errs []error
+	for _, tier := range l.tiers {
+		errs = append(errs, tier.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Stats returns the stats of the front tier.
+func (l *Layered) Stats() Stats {
+	if len(l.tiers) == 0 {
+		return Stats{}
+	}
+	return l.tiers[0].Stats()
+}
@@ -0,0 +1,81 @@
+package pokecache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLayeredPromotesFromSlowerTier(t *testing.T) {
+	d, err := NewDiskStore(t.TempDir(), time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := NewLayered(New(Config{TTL: time.Hour}), d)
+	defer first.Close()
+	first.Add("https://example.com", []byte("testdata"))
+
+	memory := New(Config{TTL: time.Hour})
+	second := NewLayered(memory, d)
+	defer second.Close()
+	if second.Stats().Entries != 0 {
+		t.Fatalf("expected a new layered cache to start with an empty front tier")
+	}
+	val, ok := second.Get("https://example.com")
+	if !ok || string(val) != "testdata" {
+		t.Errorf("expected to find value in the disk tier, got %q, %v", val, ok)
+	}
+	if _, ok := memory.Get("https://example.com"); !ok {
+		t.Errorf("expected value from disk to be promoted to memory")
+	}
+
+	second.Delete("https://example.com")
+	_, inMemory := memory.Get("https://example.com")
+	_, onDisk := d.Get("https://example.com")
+	if inMemory || onDisk {
+		t.Errorf("expected delete to reach every tier")
+	}
+}
+
+func TestNoop(t *testing.T) {
+	var store Store = Noop{}
+	if store.Add("key", []byte("val")) {
+		t.Errorf("expected Noop not to store anything")
+	}
+	if _, ok := store.Get("key"); ok {
+		t.Errorf("expected Noop to never hit")
+	}
+}
+
+var (
+	_ Store = (*Cache)(nil)
+	_ Store = (*DiskStore)(nil)
+	_ Store = (*Layered)(nil)
+	_ Store = Noop{}
+)
+
+func TestPeekIsNotAUse(t *testing.T) {
+	d, err := NewDiskStore(t.TempDir(), time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Add("https://example.com", []byte("testdata"))
+	memory := New(Config{TTL: time.Hour})
+	layered := NewLayered(memory, d)
+	defer layered.Close()
+
+	entry, ok := layered.Peek("https://example.com")
+	if !ok || string(entry.Val) != "testdata" {
+		t.Fatalf("expected to peek at the disk entry, got %q, %v", entry.Val, ok)
+	}
+	if _, ok := layered.Peek("https://example.com/missing"); ok {
+		t.Errorf("expected a missing entry not to be found")
+	}
+	if memory.Stats().Entries != 0 {
+		t.Errorf("expected Peek not to copy the entry into memory")
+	}
+	for _, stats := range []Stats{memory.Stats(), d.Stats()} {
+		if stats.Hits != 0 || stats.Misses != 0 {
+			t.Errorf("expected Peek not to count, got %+v", stats)
+		}
+	}
+}
@@ -2,12 +2,20 @@ package main
 
 import (
 	"bufio"
-	"encoding/json"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"math/rand"
+	"net/http"
 	"os"
+	"os/signal"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/tobiaspartzsch/pokedex/internal/pokeapi"
 	"github.com/tobiaspartzsch/pokedex/internal/pokecache"
@@ -15,26 +23,66 @@ import (
 
 type cliCommand struct {
 	description string
-	callback    func(*Config, []string) error
+	callback    func(context.Context, *Config, []string) error
+	// longRunning commands are not subject to -command-timeout, only to
+	// their own entry in -command-timeouts
+	longRunning bool
 }
 
 type Config struct {
-	PokeAPIConfig pokeapi.Config
-	Commands      map[string]cliCommand
-	PokeCache     *pokecache.Cache
-	Pokedex       map[string]pokeapi.Pokemon
+	PokeAPIClient   *pokeapi.Client
+	PokeAPIConfig   pokeapi.Config
+	Commands        map[string]cliCommand
+	PokeCache       pokecache.Store
+	Settings        settings
+	Pokedex         map[string]pokeapi.PokemonSummary
+	CommandTimeout  time.Duration            // deadline for a single command, 0 means none
+	CommandTimeouts map[string]time.Duration // per-command overrides of CommandTimeout
+}
+
+// commandRunner runs one command at a time and lets an interrupt cancel
+// the command in flight instead of killing the whole session.
+type commandRunner struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
 }
 
 func main() {
+	s, err := loadSettings(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	client := pokeapi.NewClient(s.APIURL, s.HTTPTimeout)
+	client.UserAgent = s.UserAgent
+	client.MaxResponseBytes = s.MaxResponseBytes
+	client.Retry = pokeapi.RetryPolicy{
+		MaxAttempts: s.RetryAttempts,
+		BaseDelay:   s.RetryBaseDelay,
+		MaxDelay:    s.RetryMaxDelay,
+	}
+	client.Limiter = nil
+	if s.RateLimit > 0 {
+		client.Limiter = pokeapi.NewRateLimiter(s.RateLimit, s.RateBurst)
+	}
+	if err := configureTransport(client, s); err != nil {
+		log.Fatal(err)
+	}
+
 	cfg := Config{
-		PokeAPIConfig: pokeapi.Config{
-			Next:     "https://pokeapi.co/api/v2/location-area", // Initialize with the base URL
-			Previous: "",                                        // No previous page initially
-		},
-		Commands:  map[string]cliCommand{},
-		PokeCache: pokecache.NewCache(10),
-		Pokedex:   make(map[string]pokeapi.Pokemon),
+		PokeAPIClient:   client,
+		PokeAPIConfig:   pokeapi.Config{Limit: pokeapi.DefaultPageSize},
+		Commands:        map[string]cliCommand{},
+		PokeCache:       newCache(s),
+		Settings:        s,
+		Pokedex:         make(map[string]pokeapi.PokemonSummary),
+		CommandTimeout:  s.CommandTimeout,
+		CommandTimeouts: s.CommandTimeouts,
 	}
+	client.Cache = cfg.PokeCache
 	cfg.Commands = map[string]cliCommand{
 		"help": {
 			description: "Displays a help message",
@@ -45,11 +93,11 @@ func main() {
 			callback:    commandExit,
 		},
 		"map": {
-			description: "Display the next 20 area locations",
+			description: "Display the next page of area locations: map [first|last|--page N] [--limit N]",
 			callback:    commandMap,
 		},
 		"mapb": {
-			description: "Display the previous 20 area locations",
+			description: "Display the previous page of area locations: mapb [first|last|--page N] [--limit N]",
 			callback:    commandMapb,
 		},
 		"explore": {
@@ -61,15 +109,39 @@ func main() {
 			callback:    commandCatch,
 		},
 		"inspect": {
-			description: "Inspect a Pokemon in your Pokedex",
+			description: "Inspect a Pokemon in your Pokedex: inspect <pokemon> [--full]",
 			callback:    commandInspect,
 		},
 		"pokedex": {
 			description: "Lists all the caught Pokemon in your Pokedex",
 			callback:    commandPokedex,
 		},
+		"cache": {
+			description: "Inspect the response cache: cache [stats|list [prefix]|get <url>|clear [prefix]|prune]",
+			callback:    commandCache,
+		},
+		"prefetch": {
+			description: "Fill the cache ahead of time: prefetch location-areas|pokemon [from-to]|region <name>|generation <name>",
+			callback:    commandPrefetch,
+			longRunning: true,
+		},
+		"ratelimit": {
+			description: "Show how much requests to the PokeAPI have been throttled",
+			callback:    commandRateLimit,
+		},
 	}
 
+	runner := &commandRunner{}
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		for range interrupts {
+			if !runner.interrupt() {
+				fmt.Print("\n(type 'exit' to quit)\nPokedex > ")
+			}
+		}
+	}()
+
 	scanner := bufio.NewScanner(os.Stdin)
 	for {
 		fmt.Print("Pokedex > ")
@@ -87,59 +159,231 @@ func main() {
 			fmt.Printf("Unknown command: %s\n", cleanInput[0])
 			continue
 		}
-		err := command.callback(&cfg, cleanInput[1:])
+		err := runner.run(&cfg, cleanInput[0], command, cleanInput[1:])
 		if err != nil {
-			fmt.Printf("Error executing %s, %v\n", cleanInput[0], err.Error())
+			fmt.Println(errorMessage(cleanInput[0], err))
 		}
 	}
 }
 
-func commandExit(cfg *Config, args []string) error {
+// errorMessage turns the error of a command into something a user can act on.
+func errorMessage(command string, err error) string {
+	var statusErr *pokeapi.StatusError
+	var transportErr *pokeapi.TransportError
+	var decodeErr *pokeapi.DecodeError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return command + " cancelled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return command + " timed out"
+	case errors.Is(err, pokeapi.ErrNotRecorded):
+		return fmt.Sprintf("That request was not recorded, record the session again with -record (%v)", err)
+	case errors.Is(err, pokeapi.ErrOffline):
+		return "That is not available offline, it is neither cached nor in the data dump."
+	case errors.Is(err, pokeapi.ErrRateLimited):
+		return "The PokeAPI is rate limiting us, please try again in a moment."
+	case errors.Is(err, pokeapi.ErrResponseTooLarge):
+		return fmt.Sprintf("The PokeAPI response for %s was too large, raise -max-response-bytes to allow it", command)
+	case errors.As(err, &transportErr):
+		return fmt.Sprintf("Could not reach the PokeAPI, check your connection (%v)", transportErr.Err)
+	case errors.As(err, &decodeErr):
+		return fmt.Sprintf("The PokeAPI sent a response %s could not understand: %v", command, decodeErr.Err)
+	case errors.As(err, &statusErr):
+		return fmt.Sprintf("The PokeAPI failed with status %d while executing %s", statusErr.StatusCode, command)
+	}
+	return fmt.Sprintf("Error executing %s, %v", command, err.Error())
+}
+
+func (r *commandRunner) run(cfg *Config, name string, command cliCommand, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if timeout := cfg.commandTimeout(name, command); timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+		defer cancelTimeout()
+	}
+
+	r.mu.Lock()
+	r.cancel = cancel
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.cancel = nil
+		r.mu.Unlock()
+	}()
+
+	return command.callback(ctx, cfg, args)
+}
+
+// interrupt cancels the running command and reports whether there was one.
+func (r *commandRunner) interrupt() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancel == nil {
+		return false
+	}
+	r.cancel()
+	return true
+}
+
+func (cfg *Config) commandTimeout(name string, command cliCommand) time.Duration {
+	if timeout, ok := cfg.CommandTimeouts[name]; ok {
+		return timeout
+	}
+	if command.longRunning {
+		return 0
+	}
+	return cfg.CommandTimeout
+}
+
+func commandExit(ctx context.Context, cfg *Config, args []string) error {
 	msg := "Closing the Pokedex... Goodbye!"
 	fmt.Println(msg)
+	cfg.PokeCache.Close()
 	os.Exit(0)
 	return nil
 }
 
-func commandMap(cfg *Config, args []string) error {
-	next := cfg.PokeAPIConfig.Next
-	if next == "" {
+func commandMap(ctx context.Context, cfg *Config, args []string) error {
+	opts, err := parseMapOptions(args)
+	if err != nil {
+		return err
+	}
+	shown := cfg.PokeAPIConfig
+	if opts.limit > 0 {
+		cfg.PokeAPIConfig.Limit = opts.limit
+	}
+	limit := cfg.PokeAPIConfig.PageSize()
+	if opts.jumps() {
+		return jumpToLocationAreaPage(ctx, cfg, limit, opts)
+	}
+
+	if !shown.Shown {
+		return fetchAndPrintLocationAreas(ctx, cfg, 0, limit)
+	}
+	// Continue right after what was shown, even if the page size changed
+	next := shown.Offset + shown.PageSize()
+	if next >= shown.Count {
 		fmt.Println("you're on the last page")
 		return nil
 	}
-	return fetchAndPrintLocationAreas(
-		cfg,
-		next,
-	)
+	return fetchAndPrintLocationAreas(ctx, cfg, next, limit)
 }
 
-func commandMapb(cfg *Config, args []string) error {
-	previous := cfg.PokeAPIConfig.Previous
-	if previous == "" {
+func commandMapb(ctx context.Context, cfg *Config, args []string) error {
+	opts, err := parseMapOptions(args)
+	if err != nil {
+		return err
+	}
+	shown := cfg.PokeAPIConfig
+	if opts.limit > 0 {
+		cfg.PokeAPIConfig.Limit = opts.limit
+	}
+	limit := cfg.PokeAPIConfig.PageSize()
+	if opts.jumps() {
+		return jumpToLocationAreaPage(ctx, cfg, limit, opts)
+	}
+
+	if !shown.Shown || shown.Offset == 0 {
 		fmt.Println("you're on the first page")
 		return nil
 	}
-	return fetchAndPrintLocationAreas(
-		cfg,
-		previous,
-	)
+	return fetchAndPrintLocationAreas(ctx, cfg, max(0, shown.Offset-limit), limit)
+}
+
+// mapOptions are the arguments of map and mapb: an optional page size
+// and an optional page to jump to instead of stepping.
+type mapOptions struct {
+	page        int // 1-based, 0 means none
+	limit       int // 0 keeps the current page size
+	first, last bool
+}
+
+func (o mapOptions) jumps() bool {
+	return o.page > 0 || o.first || o.last
 }
 
-func commandExplore(cfg *Config, args []string) error {
+// parseMapOptions parses "first", "last", "--page N" and "--limit N", the
+// latter also as --page=N and --limit=N, in any order.
+func parseMapOptions(args []string) (mapOptions, error) {
+	var opts mapOptions
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "first":
+			opts.first = true
+			continue
+		case "last":
+			opts.last = true
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || (name != "page" && name != "limit") {
+			return mapOptions{}, fmt.Errorf("unknown argument %q, expected first, last, --page N or --limit N", arg)
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return mapOptions{}, fmt.Errorf("--%s requires a number", name)
+			}
+			i++
+			value = args[i]
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return mapOptions{}, fmt.Errorf("--%s requires a positive number, got %q", name, value)
+		}
+		if name == "page" {
+			opts.page = n
+		} else {
+			opts.limit = n
+		}
+	}
+	if (opts.first && opts.last) || (opts.page > 0 && (opts.first || opts.last)) {
+		return mapOptions{}, errors.New("only one of first, last and --page can be given")
+	}
+	return opts, nil
+}
+
+// jumpToLocationAreaPage shows the page of location areas opts asks for.
+func jumpToLocationAreaPage(ctx context.Context, cfg *Config, limit int, opts mapOptions) error {
+	position := pokeapi.Config{Limit: limit, Count: cfg.PokeAPIConfig.Count}
+	if position.Count == 0 && opts.last {
+		// The count comes with every page, fetch the first to learn it
+		url := cfg.PokeAPIClient.ListURL("location-area", 0, limit)
+		first, err := cfg.PokeAPIClient.GetLocationAreas(ctx, url)
+		if err != nil {
+			return fmt.Errorf("failed to count location areas: %w", err)
+		}
+		position.Count = first.Count
+	}
+
+	page := opts.page
+	switch {
+	case opts.first:
+		page = 1
+	case opts.last:
+		page = position.Pages()
+	}
+	if position.Count > 0 && page > position.Pages() {
+		return fmt.Errorf("there is no page %d, there are %d pages of %d location areas", page, position.Pages(), limit)
+	}
+	return fetchAndPrintLocationAreas(ctx, cfg, (page-1)*limit, limit)
+}
+
+func commandExplore(ctx context.Context, cfg *Config, args []string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("explore command requires a location name")
 	}
 	locationName := args[0]
 	fmt.Printf("Exploring %s...\n", locationName)
 
-	url := "https://pokeapi.co/api/v2/location-area/" + locationName
-
-	fetchFn := func(u string) (any, error) {
-		return pokeapi.GetLocationAreaDetails(u)
+	url := cfg.PokeAPIClient.URL("location-area", locationName)
+	locationArea, err := cfg.PokeAPIClient.GetLocationAreaDetails(ctx, url)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		fmt.Println(notFoundMessage(ctx, cfg, "location-area", "location area", locationName))
+		return nil
 	}
-
-	var locationArea pokeapi.LocationArea
-	err := fetchAndCacheData(cfg, url, fetchFn, &locationArea)
 	if err != nil {
 		return fmt.Errorf("failed to explore location area: %w", err)
 	}
@@ -152,7 +396,7 @@ func commandExplore(cfg *Config, args []string) error {
 	return nil
 }
 
-func commandCatch(cfg *Config, args []string) error {
+func commandCatch(ctx context.Context, cfg *Config, args []string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("catch command requires a pokemon name")
 	}
@@ -164,26 +408,18 @@ func commandCatch(cfg *Config, args []string) error {
 		return nil
 	}
 
-	urlPokemon := "https://pokeapi.co/api/v2/pokemon/" + pokemonName
-	fetchFnPokemon := func(u string) (any, error) {
-		return pokeapi.GetPokemon(u)
+	urlPokemon := cfg.PokeAPIClient.URL("pokemon", pokemonName)
+	pokemon, err := cfg.PokeAPIClient.GetPokemonSummary(ctx, urlPokemon)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		fmt.Println(notFoundMessage(ctx, cfg, "pokemon", "Pokemon", pokemonName))
+		return nil
 	}
-
-	var pokemon pokeapi.Pokemon
-	err := fetchAndCacheData(cfg, urlPokemon, fetchFnPokemon, &pokemon)
 	if err != nil {
-		fmt.Printf("commandCatch: err %v\n", err)
 		return fmt.Errorf("failed to get pokemon information: %w", err)
 	}
 	fmt.Printf("%s has %d base experience\n", pokemonName, pokemon.BaseExperience)
 
-	urlSpecies := pokemon.Species.URL
-	fetchFnSpecies := func(u string) (any, error) {
-		return pokeapi.GetPokemonSpecies(u)
-	}
-
-	var species pokeapi.PokemonSpecies
-	err = fetchAndCacheData(cfg, urlSpecies, fetchFnSpecies, &species)
+	species, err := pokeapi.Resolve[pokeapi.PokemonSpecies](ctx, cfg.PokeAPIClient, pokemon.Species)
 	if err != nil {
 		return fmt.Errorf("failed to get Pokemon species information: %w", err)
 	}
@@ -201,7 +437,7 @@ func commandCatch(cfg *Config, args []string) error {
 	return nil
 }
 
-func commandPokedex(cfg *Config, args []string) error {
+func commandPokedex(ctx context.Context, cfg *Config, args []string) error {
 	fmt.Println("Your Pokedex:")
 	for pokemonName := range cfg.Pokedex {
 		fmt.Println(" - " + pokemonName)
@@ -209,7 +445,7 @@ func commandPokedex(cfg *Config, args []string) error {
 	return nil
 }
 
-func commandInspect(cfg *Config, args []string) error {
+func commandInspect(ctx context.Context, cfg *Config, args []string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("explore command requires a Pokemon name")
 	}
@@ -222,11 +458,44 @@ func commandInspect(cfg *Config, args []string) error {
 
 	fmt.Printf("Inspecting %s...\n", pokemonName)
 	pokemon.PrintDetails()
+	if !slices.Contains(args[1:], "--full") {
+		return nil
+	}
 
+	// The Pokedex only keeps the summary, the rest comes from the cache
+	// or the PokeAPI
+	full, err := cfg.PokeAPIClient.GetPokemon(ctx, cfg.PokeAPIClient.URL("pokemon", pokemonName))
+	if err != nil {
+		return fmt.Errorf("failed to get the full pokemon information: %w", err)
+	}
+	fmt.Println("Abilities:")
+	for _, a := range full.Abilities {
+		if a.IsHidden {
+			fmt.Printf("  - %s (hidden)\n", a.Ability.Name)
+		} else {
+			fmt.Printf("  - %s\n", a.Ability.Name)
+		}
+	}
+	fmt.Printf("Moves: %d\n", len(full.Moves))
+	fmt.Printf("Base experience: %d\n", full.BaseExperience)
+	return nil
+}
+
+func commandRateLimit(ctx context.Context, cfg *Config, args []string) error {
+	limiter := cfg.PokeAPIClient.Limiter
+	if limiter == nil {
+		fmt.Println("Rate limiting is disabled")
+		return nil
+	}
+	perSecond, burst := limiter.Limit()
+	stats := limiter.Stats()
+	fmt.Printf("Limit: %g requests/s, burst of %d\n", perSecond, burst)
+	fmt.Printf("Requests: %d (%d throttled, %d cancelled while waiting)\n", stats.Requests, stats.Throttled, stats.Cancelled)
+	fmt.Printf("Time spent waiting: %v\n", stats.Waited.Round(time.Millisecond))
 	return nil
 }
 
-func commandPrintHelp(cfg *Config, args []string) error {
+func commandPrintHelp(ctx context.Context, cfg *Config, args []string) error {
 	fmt.Println("Welcome to the Pokedex!\nUsage:")
 	fmt.Println("")
 
@@ -238,63 +507,161 @@ func commandPrintHelp(cfg *Config, args []string) error {
 
 // helper functions
 
-func fetchAndPrintLocationAreas(cfg *Config, url string) error {
-	fetchFn := func(u string) (any, error) {
-		return pokeapi.GetLocationAreas(u)
-	}
-
-	var locationAreas pokeapi.LocationAreas
-	err := fetchAndCacheData(cfg, url, fetchFn, &locationAreas)
+func fetchAndPrintLocationAreas(ctx context.Context, cfg *Config, offset, limit int) error {
+	url := cfg.PokeAPIClient.ListURL("location-area", offset, limit)
+	locationAreas, err := cfg.PokeAPIClient.GetLocationAreas(ctx, url)
 	if err != nil {
 		return fmt.Errorf("failed to fetch and print location areas: %w", err)
 	}
+	if len(locationAreas.Results) == 0 && offset > 0 {
+		return fmt.Errorf("there are only %d location areas", locationAreas.Count)
+	}
 
-	cfg.PokeAPIConfig.Next = locationAreas.Next
-	cfg.PokeAPIConfig.Previous = locationAreas.Previous
+	cfg.PokeAPIConfig = pokeapi.Config{
+		Offset: offset,
+		Limit:  limit,
+		Count:  locationAreas.Count,
+		Shown:  true,
+	}
 
 	for _, result := range locationAreas.Results {
 		fmt.Println(result.Name)
 	}
+	fmt.Printf("page %d of %d\n", cfg.PokeAPIConfig.Page(), cfg.PokeAPIConfig.Pages())
 	return nil
 }
 
-func fetchAndCacheData(
-	cfg *Config,
-	url string,
-	fetchFunc func(string) (any, error),
-	target any) error {
-
-	var rawData []byte
-	var exists bool
-
-	if rawData, exists = cfg.PokeCache.Get(url); !exists {
-		// Data not in cache, fetch it
-		data, err := fetchFunc(url) // Call the specific API fetch function
+// configureTransport decides where the requests of client go: to the
+// PokeAPI, optionally recording the responses, to a recording, or
+// nowhere when offline.
+func configureTransport(client *pokeapi.Client, s settings) error {
+	switch {
+	case s.Offline:
+		return goOffline(client, s.DataDump)
+	case s.Record != "":
+		recorder, err := pokeapi.NewRecordTransport(s.Record, client.HTTPClient.Transport)
 		if err != nil {
-			return err // Error from the fetchFunc is already descriptive
+			return err
 		}
-
-		// Marshal the fetched data to store in cache
-		rawData, err = json.Marshal(data)
+		client.HTTPClient.Transport = recorder
+	case s.Replay != "":
+		replayer, err := pokeapi.NewReplayTransport(s.Replay)
 		if err != nil {
-			return fmt.Errorf("error marshalling data from %s: %w", url, err)
+			return err
 		}
+		client.HTTPClient.Transport = replayer
+		client.Limiter = nil
+	}
+	return nil
+}
 
-		// Add to cache
-		if !cfg.PokeCache.Add(url, rawData) {
-			return fmt.Errorf("couldn't add url %v to cache", url)
+// goOffline keeps client off the network: requests are answered from the
+// data dump in dumpDir, if there is one, or fail with pokeapi.ErrOffline.
+func goOffline(client *pokeapi.Client, dumpDir string) error {
+	var transport http.RoundTripper = pokeapi.OfflineTransport{}
+	if dumpDir != "" {
+		dump, err := pokeapi.NewDumpTransport(dumpDir, client.BaseURL)
+		if err != nil {
+			return err
 		}
+		transport = dump
 	}
+	client.HTTPClient.Transport = transport
+	client.Limiter = nil
+	// Stale entries are all there is, refreshing them can only fail
+	client.Revalidate = false
+	return nil
+}
 
-	// Unmarshal the (potentially cached) raw data into the target struct
-	err := json.Unmarshal(rawData, target)
+// listAll is a page size large enough to list every resource of an
+// endpoint in one request.
+const listAll = 100000
+
+// notFoundMessage explains that no resource called name exists on endpoint
+// and suggests the closest existing name, if there is a reasonable one.
+func notFoundMessage(ctx context.Context, cfg *Config, endpoint, kind, name string) string {
+	msg := fmt.Sprintf("No %s named '%s'", kind, name)
+
+	url := cfg.PokeAPIClient.ListURL(endpoint, 0, listAll)
+	list, err := cfg.PokeAPIClient.GetResourceList(ctx, url)
 	if err != nil {
-		return fmt.Errorf("error unmarshalling raw data from %s: %w", url, err)
+		return msg
 	}
 
-	return nil
+	names := make([]string, 0, len(list.Results))
+	for _, result := range list.Results {
+		names = append(names, result.Name)
+	}
+	if suggestion, ok := closestName(name, names); ok {
+		msg += fmt.Sprintf(" — did you mean %s?", suggestion)
+	}
+	return msg
+}
+
+// closestName returns the candidate with the smallest edit distance to
+// name, as long as it is close enough to plausibly be a typo.
+func closestName(name string, candidates []string) (string, bool) {
+	best, bestDistance := "", -1
+	for _, candidate := range candidates {
+		d := levenshtein(name, candidate)
+		if bestDistance < 0 || d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	maxDistance := max(2, len([]rune(name))/3)
+	if bestDistance < 0 || bestDistance > maxDistance {
+		return "", false
+	}
+	return best, true
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
 }
 
 func cleanInput(text string) []string {
 	return strings.Fields(strings.ToLower(text))
 }
+
+// durationMap is a flag.Value for comma separated name=duration pairs.
+type durationMap map[string]time.Duration
+
+func (m durationMap) String() string {
+	pairs := make([]string, 0, len(m))
+	for name, d := range m {
+		pairs = append(pairs, name+"="+d.String())
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m durationMap) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		name, raw, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return fmt.Errorf("expected name=duration, got %q", pair)
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration for %s: %w", name, err)
+		}
+		m[name] = d
+	}
+	return nil
+}
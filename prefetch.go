@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tobiaspartzsch/pokedex/internal/pokeapi"
+)
+
+func commandPrefetch(ctx context.Context, cfg *Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("prefetch requires what to fetch: location-areas, pokemon [from-to], region <name> or generation <name>")
+	}
+
+	p := newPrefetcher(cfg, cfg.Settings.PrefetchWorkers, os.Stdout)
+	switch args[0] {
+	case "location-areas":
+		p.locationAreas(ctx, cfg.PokeAPIConfig.PageSize())
+	case "pokemon":
+		offset, limit := 0, listAll
+		if len(args) > 1 {
+			var err error
+			offset, limit, err = parseRange(args[1])
+			if err != nil {
+				return err
+			}
+		}
+		p.pokemonList(ctx, cfg.PokeAPIClient.ListURL("pokemon", offset, limit))
+	case "region":
+		if len(args) < 2 {
+			return errors.New("prefetch region requires a region name, e.g. kanto")
+		}
+		p.region(ctx, cfg.PokeAPIClient.URL("region", args[1]))
+	case "generation":
+		if len(args) < 2 {
+			return errors.New("prefetch generation requires a generation name or id, e.g. generation-i or 1")
+		}
+		p.generation(ctx, cfg.PokeAPIClient.URL("generation", args[1]))
+	default:
+		return fmt.Errorf("unknown prefetch target %q, expected location-areas, pokemon, region or generation", args[0])
+	}
+
+	start := time.Now()
+	fetched, failed, err := p.wait()
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if ctx.Err() != nil {
+		// Whatever was fetched is cached, so say how far it got
+		fmt.Printf("Prefetched %d resources in %v before stopping, %d were not fetched\n", fetched, elapsed, failed)
+		return ctx.Err()
+	}
+	fmt.Printf("Prefetched %d resources in %v\n", fetched, elapsed)
+	if failed > 0 {
+		fmt.Printf("%d resources could not be fetched, the first failure was: %s\n", failed, errorMessage("prefetch", err))
+	}
+	return nil
+}
+
+// parseRange parses a 1-based, inclusive range of ids such as "1-151" or
+// a single id into the offset and limit of a list page.
+func parseRange(s string) (offset, limit int, err error) {
+	fromStr, toStr, isRange := strings.Cut(s, "-")
+	if !isRange {
+		toStr = fromStr
+	}
+	from, err := strconv.Atoi(fromStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range %q, expected e.g. 1-151", s)
+	}
+	to, err := strconv.Atoi(toStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range %q, expected e.g. 1-151", s)
+	}
+	if from < 1 || to < from {
+		return 0, 0, fmt.Errorf("invalid range %q, ids start at 1 and the range must not be empty", s)
+	}
+	return from - 1, to - from + 1, nil
+}
+
+// prefetcher walks PokeAPI resources and the links between them
+// concurrently. Everything goes through the client's cache, under the
+// URLs the other commands look resources up by.
+type prefetcher struct {
+	cfg      *Config
+	sem      chan struct{} // bounds the fetches in flight
+	wg       sync.WaitGroup
+	progress *progressBar
+
+	mu       sync.Mutex
+	queued   map[string]bool
+	fetched  int
+	failed   int
+	firstErr error
+}
+
+func newPrefetcher(cfg *Config, workers int, out io.Writer) *prefetcher {
+	return &prefetcher{
+		cfg:      cfg,
+		sem:      make(chan struct{}, max(1, workers)),
+		progress: &progressBar{out: out},
+		queued:   map[string]bool{},
+	}
+}
+
+// prefetchURL fetches url in the background, unless it was queued before,
+// and passes the decoded resource to then, which may queue more.
+func prefetchURL[T any](ctx context.Context, p *prefetcher, url string, then func(T)) {
+	p.mu.Lock()
+	if p.queued[url] {
+		p.mu.Unlock()
+		return
+	}
+	p.queued[url] = true
+	p.mu.Unlock()
+	p.progress.add()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		select {
+		case p.sem <- struct{}{}:
+		case <-ctx.Done():
+			p.done(ctx.Err())
+			return
+		}
+		resource, err := pokeapi.Get[T](ctx, p.cfg.PokeAPIClient, url)
+		<-p.sem
+		p.done(err)
+		if err == nil && then != nil {
+			then(resource)
+		}
+	}()
+}
+
+func (p *prefetcher) done(err error) {
+	p.mu.Lock()
+	if err != nil {
+		p.failed++
+		if p.firstErr == nil {
+			p.firstErr = err
+		}
+	} else {
+		p.fetched++
+	}
+	p.mu.Unlock()
+	p.progress.advance()
+}
+
+// wait blocks until everything queued has been fetched.
+func (p *prefetcher) wait() (fetched, failed int, firstErr error) {
+	p.wg.Wait()
+	p.progress.finish()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.fetched, p.failed, p.firstErr
+}
+
+// locationAreas fetches the location-area list in pages of limit, the way
+// map does, and every area the way explore does. The first page tells how
+// many there are, the remaining pages are then fetched concurrently.
+func (p *prefetcher) locationAreas(ctx context.Context, limit int) {
+	client := p.cfg.PokeAPIClient
+	explore := func(page pokeapi.LocationAreas) {
+		for _, result := range page.Results {
+			p.locationArea(ctx, result.Name)
+		}
+	}
+	prefetchURL(ctx, p, client.ListURL("location-area", 0, limit), func(first pokeapi.LocationAreas) {
+		explore(first)
+		for offset := limit; offset < first.Count; offset += limit {
+			prefetchURL(ctx, p, client.ListURL("location-area", offset, limit), explore)
+		}
+	})
+}
+
+func (p *prefetcher) locationArea(ctx context.Context, name string) {
+	client := p.cfg.PokeAPIClient
+	prefetchURL(ctx, p, client.URL("location-area", name), func(area pokeapi.LocationArea) {
+		for _, encounter := range area.PokemonEncounters {
+			p.pokemon(ctx, encounter.Pokemon.Name)
+		}
+	})
+}
+
+func (p *prefetcher) pokemonList(ctx context.Context, url string) {
+	prefetchURL(ctx, p, url, func(list pokeapi.NamedAPIResourceList) {
+		for _, result := range list.Results {
+			p.pokemon(ctx, result.Name)
+		}
+	})
+}
+
+// pokemon fetches a Pokemon and its species, the way catch does.
+func (p *prefetcher) pokemon(ctx context.Context, name string) {
+	client := p.cfg.PokeAPIClient
+	prefetchURL(ctx, p, client.URL("pokemon", name), func(pokemon pokeapi.Pokemon) {
+		prefetchURL[pokeapi.PokemonSpecies](ctx, p, pokemon.Species.URL, nil)
+	})
+}
+
+func (p *prefetcher) region(ctx context.Context, url string) {
+	prefetchURL(ctx, p, url, func(region pokeapi.Region) {
+		for _, location := range region.Locations {
+			prefetchURL(ctx, p, location.URL, func(location pokeapi.Location) {
+				for _, area := range location.Areas {
+					p.locationArea(ctx, area.Name)
+				}
+			})
+		}
+	})
+}
+
+// generation fetches every species introduced in a generation and their
+// default Pokemon.
+func (p *prefetcher) generation(ctx context.Context, url string) {
+	prefetchURL(ctx, p, url, func(generation pokeapi.Generation) {
+		for _, species := range generation.PokemonSpecies {
+			prefetchURL(ctx, p, species.URL, func(species pokeapi.PokemonSpecies) {
+				for _, variety := range species.Varieties {
+					if variety.IsDefault {
+						p.pokemon(ctx, variety.Pokemon.Name)
+					}
+				}
+			})
+		}
+	})
+}
+
+// progressBar draws a single line progress bar. The total grows while
+// the prefetcher discovers more resources.
+type progressBar struct {
+	out         io.Writer
+	mu          sync.Mutex
+	done, total int
+}
+
+const progressBarWidth = 30
+
+func (b *progressBar) add() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.total++
+	b.render()
+}
+
+func (b *progressBar) advance() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done++
+	b.render()
+}
+
+func (b *progressBar) finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	fmt.Fprintln(b.out)
+}
+
+// render redraws the bar. The caller must hold b.mu.
+func (b *progressBar) render() {
+	filled := 0
+	if b.total > 0 {
+		filled = progressBarWidth * b.done / b.total
+	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	fmt.Fprintf(b.out, "\r[%s] %d/%d", bar, b.done, b.total)
+}
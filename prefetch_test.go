@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tobiaspartzsch/pokedex/internal/pokeapi"
+	"github.com/tobiaspartzsch/pokedex/internal/pokecache"
+)
+
+func TestParseRange(t *testing.T) {
+	cases := []struct {
+		input         string
+		offset, limit int
+		wantErr       bool
+	}{
+		{input: "1-151", offset: 0, limit: 151},
+		{input: "152-251", offset: 151, limit: 100},
+		{input: "25", offset: 24, limit: 1},
+		{input: "0-10", wantErr: true},
+		{input: "10-1", wantErr: true},
+		{input: "kanto", wantErr: true},
+	}
+	for _, c := range cases {
+		offset, limit, err := parseRange(c.input)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("parseRange(%q): expected an error", c.input)
+			}
+			continue
+		}
+		if err != nil || offset != c.offset || limit != c.limit {
+			t.Errorf("parseRange(%q) = %d, %d, %v, expected %d, %d", c.input, offset, limit, err, c.offset, c.limit)
+		}
+	}
+}
+
+func TestPrefetchPokemon(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base := server.URL + "/api/v2"
+		switch r.URL.Path {
+		case "/api/v2/pokemon":
+			fmt.Fprintf(w, `{"count": 2, "results": [{"name": "bulbasaur", "url": "%[1]s/pokemon/1/"}, {"name": "ivysaur", "url": "%[1]s/pokemon/2/"}]}`, base)
+		case "/api/v2/pokemon/bulbasaur":
+			fmt.Fprintf(w, `{"id": 1, "name": "bulbasaur", "species": {"name": "bulbasaur", "url": "%s/pokemon-species/1/"}}`, base)
+		case "/api/v2/pokemon/ivysaur":
+			fmt.Fprintf(w, `{"id": 2, "name": "ivysaur", "species": {"name": "ivysaur", "url": "%s/pokemon-species/2/"}}`, base)
+		case "/api/v2/pokemon-species/1/", "/api/v2/pokemon-species/2/":
+			fmt.Fprint(w, `{"capture_rate": 45}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(server.URL+"/api/v2", time.Second)
+	client.Limiter = nil
+	memory := pokecache.New(pokecache.Config{TTL: time.Hour})
+	defer memory.Close()
+	client.Cache = memory
+	cfg := &Config{PokeAPIClient: client, PokeCache: memory}
+
+	p := newPrefetcher(cfg, 2, &bytes.Buffer{})
+	p.pokemonList(context.Background(), client.ListURL("pokemon", 0, 2))
+	fetched, failed, err := p.wait()
+	if failed != 0 {
+		t.Fatalf("expected no failures, got %d: %v", failed, err)
+	}
+	if fetched != 5 {
+		t.Errorf("expected the list, 2 Pokemon and 2 species to be fetched, got %d", fetched)
+	}
+	for _, url := range []string{
+		client.URL("pokemon", "bulbasaur"),
+		client.URL("pokemon", "ivysaur"),
+		server.URL + "/api/v2/pokemon-species/2/",
+	} {
+		if _, ok := memory.Get(url); !ok {
+			t.Errorf("expected %s to be cached", url)
+		}
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	var out bytes.Buffer
+	bar := &progressBar{out: &out}
+	bar.add()
+	bar.add()
+	bar.advance()
+	line := out.String()[strings.LastIndex(out.String(), "\r")+1:]
+	if want := "[" + strings.Repeat("=", 15) + strings.Repeat(" ", 15) + "] 1/2"; line != want {
+		t.Errorf("expected %q, got %q", want, line)
+	}
+}
+
+func TestPrefetchDeadline(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base := server.URL + "/api/v2"
+		switch r.URL.Path {
+		case "/api/v2/pokemon":
+			fmt.Fprintf(w, `{"count": 2, "results": [{"name": "bulbasaur", "url": "%[1]s/pokemon/1/"}, {"name": "ivysaur", "url": "%[1]s/pokemon/2/"}]}`, base)
+		case "/api/v2/pokemon/bulbasaur":
+			fmt.Fprintf(w, `{"id": 1, "name": "bulbasaur", "species": {"name": "bulbasaur", "url": "%s/pokemon-species/1/"}}`, base)
+		case "/api/v2/pokemon-species/1/":
+			fmt.Fprint(w, `{"capture_rate": 45}`)
+		default:
+			// Hangs until the client gives up
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(server.URL+"/api/v2", time.Minute)
+	client.Limiter = nil
+	memory := pokecache.New(pokecache.Config{TTL: time.Hour})
+	defer memory.Close()
+	client.Cache = memory
+	cfg := &Config{
+		PokeAPIClient:   client,
+		PokeCache:       memory,
+		Settings:        settings{PrefetchWorkers: 2},
+		CommandTimeout:  time.Millisecond,
+		CommandTimeouts: map[string]time.Duration{},
+	}
+	command := cliCommand{callback: commandPrefetch, longRunning: true}
+	if got := cfg.commandTimeout("prefetch", command); got != 0 {
+		t.Errorf("expected prefetch to be exempt from -command-timeout, got %v", got)
+	}
+
+	cfg.CommandTimeouts["prefetch"] = 200 * time.Millisecond
+	var err error
+	output := captureOutput(t, func() {
+		err = (&commandRunner{}).run(cfg, "prefetch", command, []string{"pokemon", "1-2"})
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if !strings.Contains(output, "Prefetched 3 resources") || !strings.Contains(output, "1 were not fetched") {
+		t.Errorf("expected a summary of the partial prefetch, got %q", output)
+	}
+}
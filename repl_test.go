@@ -1,6 +1,19 @@
 package main
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tobiaspartzsch/pokedex/internal/pokeapi"
+	"github.com/tobiaspartzsch/pokedex/internal/pokeapitest"
+	"github.com/tobiaspartzsch/pokedex/internal/pokecache"
+)
 
 func TestCleanInput(t *testing.T) {
 	cases := []struct {
@@ -67,3 +80,286 @@ at index %d: Expected \"%s\" but found \"%s\"!"
 		}
 	}
 }
+
+func TestDurationMapSet(t *testing.T) {
+	m := durationMap{}
+	if err := m.Set("catch=5s, explore=1m"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m["catch"] != 5*time.Second || m["explore"] != time.Minute {
+		t.Errorf("unexpected durations: %v", m)
+	}
+	if err := m.Set("catch"); err == nil {
+		t.Errorf("expected error for missing duration")
+	}
+}
+
+func TestCommandRunnerInterrupt(t *testing.T) {
+	runner := &commandRunner{}
+	started := make(chan struct{})
+	command := cliCommand{
+		callback: func(ctx context.Context, cfg *Config, args []string) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	go func() {
+		<-started
+		runner.interrupt()
+	}()
+
+	err := runner.run(&Config{}, "blocking", command, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if runner.interrupt() {
+		t.Errorf("expected no command to be running after run returned")
+	}
+}
+
+func TestCommandRunnerTimeout(t *testing.T) {
+	runner := &commandRunner{}
+	cfg := &Config{
+		CommandTimeout:  time.Hour,
+		CommandTimeouts: map[string]time.Duration{"slow": time.Millisecond},
+	}
+	command := cliCommand{
+		callback: func(ctx context.Context, cfg *Config, args []string) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	err := runner.run(cfg, "slow", command, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestClosestName(t *testing.T) {
+	candidates := []string{"pikachu", "raichu", "pichu", "bulbasaur"}
+	cases := []struct {
+		input    string
+		expected string
+		found    bool
+	}{
+		{input: "pikachuu", expected: "pikachu", found: true},
+		{input: "bulbasuar", expected: "bulbasaur", found: true},
+		{input: "charizard", found: false},
+	}
+	for _, c := range cases {
+		actual, found := closestName(c.input, candidates)
+		if found != c.found || actual != c.expected {
+			t.Errorf("closestName(%q) = %q, %v; expected %q, %v", c.input, actual, found, c.expected, c.found)
+		}
+	}
+}
+
+func TestParseMapOptions(t *testing.T) {
+	opts, err := parseMapOptions([]string{"--limit", "50", "--page=3"})
+	if err != nil || opts.limit != 50 || opts.page != 3 {
+		t.Errorf("unexpected options %+v, %v", opts, err)
+	}
+	opts, err = parseMapOptions([]string{"last", "--limit=10"})
+	if err != nil || !opts.last || opts.limit != 10 {
+		t.Errorf("unexpected options %+v, %v", opts, err)
+	}
+	for _, args := range [][]string{
+		{"--page"},
+		{"--page", "0"},
+		{"--limit", "many"},
+		{"first", "last"},
+		{"--page", "2", "first"},
+		{"sideways"},
+	} {
+		if _, err := parseMapOptions(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+// newTestConfig returns a Config whose client talks to a fake PokeAPI.
+func newTestConfig(t *testing.T) (*Config, *pokeapitest.Server) {
+	t.Helper()
+	server := pokeapitest.NewServer()
+	t.Cleanup(server.Close)
+	memory := pokecache.New(pokecache.Config{TTL: time.Hour})
+	t.Cleanup(func() { memory.Close() })
+	client := server.NewClient()
+	client.Cache = memory
+	return &Config{
+		PokeAPIClient: client,
+		PokeCache:     memory,
+		Pokedex:       map[string]pokeapi.PokemonSummary{},
+	}, server
+}
+
+// captureOutput returns what fn prints to stdout.
+func captureOutput(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-output
+}
+
+func TestMapNavigation(t *testing.T) {
+	cfg, _ := newTestConfig(t)
+	ctx := context.Background()
+
+	steps := []struct {
+		command func(context.Context, *Config, []string) error
+		args    []string
+		offset  int
+		limit   int
+	}{
+		{commandMap, nil, 0, 20},
+		{commandMap, nil, 20, 20},
+		{commandMapb, nil, 0, 20},
+		{commandMap, []string{"last"}, 20, 20},
+		{commandMap, []string{"--limit", "10"}, 20, 10}, // still the last page, stays put
+		{commandMapb, nil, 10, 10},
+		{commandMap, []string{"--page", "2", "--limit", "15"}, 15, 15},
+		{commandMapb, []string{"first"}, 0, 15},
+	}
+	for i, step := range steps {
+		if err := step.command(ctx, cfg, step.args); err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		position := cfg.PokeAPIConfig
+		if position.Offset != step.offset || position.PageSize() != step.limit || position.Count != 25 {
+			t.Fatalf("step %d: expected offset %d and limit %d, got %+v", i, step.offset, step.limit, position)
+		}
+	}
+
+	if err := commandMap(ctx, cfg, []string{"--page", "3"}); err == nil {
+		t.Errorf("expected an error for a page past the end")
+	}
+}
+
+func TestMapOutput(t *testing.T) {
+	cfg, _ := newTestConfig(t)
+	var err error
+	output := captureOutput(t, func() {
+		err = commandMap(context.Background(), cfg, []string{"--limit", "2"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "canalave-city-area\neterna-city-area\npage 1 of 13\n"
+	if output != want {
+		t.Errorf("expected %q, got %q", want, output)
+	}
+}
+
+func TestExplore(t *testing.T) {
+	cfg, _ := newTestConfig(t)
+	ctx := context.Background()
+
+	output := captureOutput(t, func() {
+		if err := commandExplore(ctx, cfg, []string{"canalave-city-area"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Found Pokemon:\n - tentacool\n - tentacruel\n") {
+		t.Errorf("unexpected output %q", output)
+	}
+
+	output = captureOutput(t, func() {
+		if err := commandExplore(ctx, cfg, []string{"canalave-city-are"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(output, "did you mean canalave-city-area?") {
+		t.Errorf("expected a suggestion, got %q", output)
+	}
+}
+
+func TestCatch(t *testing.T) {
+	cfg, server := newTestConfig(t)
+	ctx := context.Background()
+	// rand.Intn(256) is always below 256 and never below 0
+	server.SetResource("pokemon-species", "pikachu", `{"id": 25, "name": "pikachu", "capture_rate": 256}`)
+	server.SetResource("pokemon-species", "magikarp", `{"id": 129, "name": "magikarp", "capture_rate": 0}`)
+
+	output := captureOutput(t, func() {
+		if err := commandCatch(ctx, cfg, []string{"pikachu"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if _, caught := cfg.Pokedex["pikachu"]; !caught || !strings.Contains(output, "pikachu was caught!") {
+		t.Errorf("expected pikachu to be caught, got %q", output)
+	}
+
+	server.FailNext("pokemon/magikarp", http.StatusTooManyRequests, 1)
+	output = captureOutput(t, func() {
+		if err := commandCatch(ctx, cfg, []string{"magikarp"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if _, caught := cfg.Pokedex["magikarp"]; caught || !strings.Contains(output, "magikarp escaped!") {
+		t.Errorf("expected magikarp to escape, got %q", output)
+	}
+	if got := server.Requests("pokemon/magikarp"); got != 2 {
+		t.Errorf("expected the rate limited request to be retried once, got %d requests", got)
+	}
+
+	output = captureOutput(t, func() {
+		if err := commandCatch(ctx, cfg, []string{"pikachoo"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(output, "No Pokemon named 'pikachoo' — did you mean pikachu?") {
+		t.Errorf("expected a suggestion, got %q", output)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	cfg, server := newTestConfig(t)
+	ctx := context.Background()
+	server.SetResource("pokemon-species", "pikachu", `{"id": 25, "name": "pikachu", "capture_rate": 256}`)
+	captureOutput(t, func() {
+		if err := commandCatch(ctx, cfg, []string{"pikachu"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if got := cfg.Pokedex["pikachu"]; got.Height != 4 || len(got.Types) == 0 || len(got.Stats) == 0 {
+		t.Fatalf("expected the summary of pikachu in the Pokedex, got %+v", got)
+	}
+
+	output := captureOutput(t, func() {
+		if err := commandInspect(ctx, cfg, []string{"pikachu"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Height: 4") || !strings.Contains(output, "  - electric") || strings.Contains(output, "Abilities:") {
+		t.Errorf("unexpected inspect output %q", output)
+	}
+
+	requests := server.Requests("pokemon/pikachu")
+	output = captureOutput(t, func() {
+		if err := commandInspect(ctx, cfg, []string{"pikachu", "--full"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(output, "  - static") || !strings.Contains(output, "Moves: ") {
+		t.Errorf("expected abilities and moves with --full, got %q", output)
+	}
+	if got := server.Requests("pokemon/pikachu"); got != requests {
+		t.Errorf("expected the full Pokemon to come from the cache, got %d more requests", got-requests)
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tobiaspartzsch/pokedex/internal/pokeapi"
+	"github.com/tobiaspartzsch/pokedex/internal/pokecache"
+)
+
+const envPrefix = "POKEDEX_"
+
+// settings holds everything that can be configured at startup. Every
+// setting is a flag, and can also be given in the JSON config file (keyed
+// by flag name) or as an environment variable (POKEDEX_ followed by the
+// flag name in upper case with dashes replaced by underscores).
+// Flags win over the environment, which wins over the config file.
+type settings struct {
+	APIURL           string
+	HTTPTimeout      time.Duration
+	UserAgent        string
+	MaxResponseBytes int64
+	RetryAttempts    int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+	RateLimit        float64
+	RateBurst        int
+	CommandTimeout   time.Duration
+	CommandTimeouts  durationMap
+	CacheTTL         time.Duration
+	CacheStaleTTL    time.Duration
+	CacheReap        time.Duration
+	CacheMaxEntries  int
+	CacheMaxBytes    int64
+	CacheDir         string
+	Offline          bool
+	DataDump         string
+	PrefetchWorkers  int
+	Record           string
+	Replay           string
+}
+
+func newFlagSet(s *settings) *flag.FlagSet {
+	fs := flag.NewFlagSet("pokedex", flag.ContinueOnError)
+	fs.StringVar(&s.APIURL, "api-url", pokeapi.DefaultBaseURL, "base URL of the PokeAPI instance")
+	fs.DurationVar(&s.HTTPTimeout, "http-timeout", pokeapi.DefaultTimeout, "timeout for a single PokeAPI request")
+	fs.StringVar(&s.UserAgent, "user-agent", pokeapi.DefaultUserAgent, "User-Agent header sent to the PokeAPI")
+	fs.Int64Var(&s.MaxResponseBytes, "max-response-bytes", pokeapi.DefaultMaxResponseBytes, "maximum size of a PokeAPI response body in bytes, 0 means unbounded")
+	fs.IntVar(&s.RetryAttempts, "retry-attempts", pokeapi.DefaultRetryPolicy.MaxAttempts, "attempts per PokeAPI request before giving up, 1 disables retries")
+	fs.DurationVar(&s.RetryBaseDelay, "retry-base-delay", pokeapi.DefaultRetryPolicy.BaseDelay, "backoff before the first retry, doubled for every further one")
+	fs.DurationVar(&s.RetryMaxDelay, "retry-max-delay", pokeapi.DefaultRetryPolicy.MaxDelay, "upper bound for a single wait between retries, 0 means unbounded")
+	fs.Float64Var(&s.RateLimit, "rate-limit", pokeapi.DefaultRequestsPerSecond, "maximum PokeAPI requests per second, 0 disables throttling")
+	fs.IntVar(&s.RateBurst, "rate-burst", pokeapi.DefaultBurst, "number of PokeAPI requests allowed in a burst")
+	fs.DurationVar(&s.CommandTimeout, "command-timeout", 30*time.Second, "deadline for a single command, 0 disables it")
+	s.CommandTimeouts = durationMap{}
+	fs.Var(s.CommandTimeouts, "command-timeouts", "per-command deadlines, e.g. catch=5s,explore=1m")
+	fs.DurationVar(&s.CacheTTL, "cache-ttl", time.Hour, "how long PokeAPI responses are cached")
+	fs.DurationVar(&s.CacheStaleTTL, "cache-stale-ttl", 24*time.Hour, "how long expired responses are still served while they are refreshed in the background")
+	fs.DurationVar(&s.CacheReap, "cache-reap-interval", 5*time.Minute, "how often expired cache entries are removed")
+	fs.IntVar(&s.CacheMaxEntries, "cache-max-entries", 1000, "maximum number of cached responses, 0 means unbounded")
+	fs.StringVar(&s.CacheDir, "cache-dir", defaultCacheDir(), "directory the cache is persisted in, empty keeps it in memory only")
+	fs.Int64Var(&s.CacheMaxBytes, "cache-max-bytes", 64<<20, "maximum size of cached responses in bytes, 0 means unbounded")
+	fs.BoolVar(&s.Offline, "offline", false, "never go to the network, serve from the cache and the data dump only")
+	fs.StringVar(&s.DataDump, "data-dump", "", "directory with a PokeAPI data dump (api-data layout) to serve from in offline mode")
+	fs.IntVar(&s.PrefetchWorkers, "prefetch-workers", 8, "number of resources prefetch fetches at once")
+	fs.StringVar(&s.Record, "record", "", "directory to record every PokeAPI response to, for replaying later")
+	fs.StringVar(&s.Replay, "replay", "", "directory of recorded PokeAPI responses to serve instead of going to the network")
+	return fs
+}
+
+func loadSettings(args []string) (settings, error) {
+	var s settings
+	fs := newFlagSet(&s)
+	configPath := fs.String("config", defaultConfigPath(), "path of the JSON config file")
+	// The first pass only finds the config file, the second one makes
+	// flags win over whatever the file and environment set.
+	if err := fs.Parse(args); err != nil {
+		return settings{}, err
+	}
+	explicitConfig := false
+	fs.Visit(func(f *flag.Flag) {
+		explicitConfig = explicitConfig || f.Name == "config"
+	})
+
+	if err := applyConfigFile(fs, *configPath, explicitConfig); err != nil {
+		return settings{}, err
+	}
+	if err := applyEnv(fs); err != nil {
+		return settings{}, err
+	}
+	if err := fs.Parse(args); err != nil {
+		return settings{}, err
+	}
+
+	modes := 0
+	for _, set := range []bool{s.Offline, s.Record != "", s.Replay != ""} {
+		if set {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return settings{}, errors.New("only one of -offline, -record and -replay can be used at a time")
+	}
+	return s, nil
+}
+
+func defaultConfigPath() string {
+	if path, ok := os.LookupEnv(envName("config")); ok {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pokedex", "config.json")
+}
+
+// applyConfigFile sets flags from a JSON object keyed by flag name. A
+// missing file is only an error if its path was given explicitly.
+func applyConfigFile(fs *flag.FlagSet, path string, mustExist bool) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !mustExist {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't read config file: %w", err)
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("couldn't parse config file %s: %w", path, err)
+	}
+	for name, raw := range values {
+		if name == "config" || fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown setting %q in config file %s", name, path)
+		}
+		// Numbers and booleans are passed on as written, so that large
+		// integers don't turn into floats such as 6.7108864e+07
+		value := string(raw)
+		if strings.HasPrefix(value, `"`) {
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return fmt.Errorf("invalid value for %s in config file %s: %w", name, path, err)
+			}
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("invalid value for %s in config file %s: %w", name, path, err)
+		}
+	}
+	return nil
+}
+
+func applyEnv(fs *flag.FlagSet) error {
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || f.Name == "config" {
+			return
+		}
+		name := envName(f.Name)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value for %s: %w", name, setErr)
+		}
+	})
+	return err
+}
+
+func defaultCacheDir() string {
+	dir, err := pokecache.DefaultDiskDir()
+	if err != nil {
+		return ""
+	}
+	return dir
+}
+
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
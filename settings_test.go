@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadSettingsPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	config := `{"cache-ttl": "10m", "cache-max-entries": 50, "rate-limit": 2, "cache-max-bytes": 67108864, "offline": true}`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("POKEDEX_CONFIG", path)
+	t.Setenv("POKEDEX_CACHE_MAX_ENTRIES", "75")
+	t.Setenv("POKEDEX_RATE_LIMIT", "3")
+
+	s, err := loadSettings([]string{"-rate-limit", "4"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.CacheTTL != 10*time.Minute {
+		t.Errorf("expected cache TTL from config file, got %v", s.CacheTTL)
+	}
+	if s.CacheMaxEntries != 75 {
+		t.Errorf("expected environment to override config file, got %d", s.CacheMaxEntries)
+	}
+	if s.RateLimit != 4 {
+		t.Errorf("expected flag to override environment, got %v", s.RateLimit)
+	}
+	if s.CacheReap != 5*time.Minute {
+		t.Errorf("expected default reap interval, got %v", s.CacheReap)
+	}
+	if s.CacheMaxBytes != 64<<20 || !s.Offline {
+		t.Errorf("expected integers and booleans from the config file, got %d, %v", s.CacheMaxBytes, s.Offline)
+	}
+}
+
+func TestLoadSettingsConfigFile(t *testing.T) {
+	t.Setenv("POKEDEX_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := loadSettings(nil); err != nil {
+		t.Errorf("expected a missing default config file to be ignored, got %v", err)
+	}
+	if _, err := loadSettings([]string{"-config", filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Errorf("expected an explicitly given missing config file to fail")
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"cache-size": 10}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadSettings([]string{"-config", path}); err == nil {
+		t.Errorf("expected an unknown setting to fail")
+	}
+}
+
+func TestLoadSettingsExclusiveModes(t *testing.T) {
+	t.Setenv("POKEDEX_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := loadSettings([]string{"-record", t.TempDir(), "-offline"}); err == nil {
+		t.Errorf("expected -record and -offline to be rejected together")
+	}
+	if _, err := loadSettings([]string{"-replay", t.TempDir()}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}